@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/record"
+)
+
+// runAnalyze 实现 `tracklogicchess analyze`：给出局面中每个合法着法的分数、胜负步数与主变。
+// 局面由起始局面（初始局面或 -position）加一串着法给出（-moves，或 -file 指定的文件），
+// 也可以取自对局记录（-game，-ply 指定第几步之后）。
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	outerFlag := fs.Int("outer", 0, "外圈旋转方向（0=顺时针,1=逆时针）")
+	innerFlag := fs.Int("inner", 0, "内圈旋转方向（0=顺时针,1=逆时针）")
+	position := fs.String("position", "", "起始局面记法（旋转方向以记法为准）")
+	movesFlag := fs.String("moves", "", "从起始局面走出的着法，例如 \"1,1 0,0 2,2\"")
+	file := fs.String("file", "", "从文件读取着法序列（格式同 -moves，可分多行）")
+	gamePath := fs.String("game", "", "对局记录文件（见 record 包），分析其中的局面")
+	ply := fs.Int("ply", -1, "与 -game 一起使用：分析第几步之后的局面（-1 表示最后）")
+	depth := fs.Int("depth", 8, "搜索深度（0 表示搜到终局）")
+	moveTime := fs.Duration("movetime", 0, "分析时间上限，例如 10s（0 表示不限）")
+	_ = fs.Parse(args)
+
+	var g *game.GameState
+	if *gamePath != "" {
+		var err error
+		if g, err = recordState(*gamePath, *ply); err != nil {
+			fmt.Println("读取对局记录失败：", err)
+			return
+		}
+	} else {
+		var ok bool
+		if g, ok = startState(*position, *outerFlag, *innerFlag); !ok {
+			return
+		}
+	}
+	text := *movesFlag
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			fmt.Println("读取着法文件失败：", err)
+			return
+		}
+		text = string(data)
+	}
+	moves, err := parseMoves(text)
+	if err != nil {
+		fmt.Println("着法格式错误：", err)
+		return
+	}
+
+	for i, mv := range moves {
+		if err := g.ApplyMove(mv.Row, mv.Col); err != nil {
+			fmt.Printf("第 %d 步 %v 无效：%v\n", i+1, mv, err)
+			return
+		}
+	}
+
+	fmt.Printf("外圈%s，内圈%s。\n", directionString(g.DirOuter), directionString(g.DirInner))
+	fmt.Println(g.Board.String())
+	fmt.Println("局面记法：", game.FormatPosition(g))
+	if g.IsGameOver() {
+		fmt.Println("对局已结束：", g.Result())
+		return
+	}
+	fmt.Printf("轮到 %s 走棋。\n\n", g.CurrentPlayer)
+
+	res := g.Analyze(context.Background(), game.Limits{Depth: *depth, MoveTime: *moveTime})
+	for _, a := range res {
+		fmt.Printf("  %v  分数 %8d  %-8s  主变 %v\n", a.Move, a.Score, mateString(a.Mate), a.PV)
+	}
+}
+
+// recordState 读取对局记录并重走，返回第 ply 步之后的局面（ply < 0 表示最后）。
+func recordState(path string, ply int) (*game.GameState, error) {
+	rec, err := record.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	states, err := rec.Replay()
+	if err != nil {
+		return nil, err
+	}
+	if ply < 0 {
+		ply = len(states) - 1
+	}
+	if ply >= len(states) {
+		return nil, fmt.Errorf("记录只有 %d 步", len(states)-1)
+	}
+	return states[ply], nil
+}
+
+// mateString 把胜负步数转为说明文字，未证明时为空。
+func mateString(mate int) string {
+	switch {
+	case mate > 0:
+		return fmt.Sprintf("%d 步胜", mate)
+	case mate < 0:
+		return fmt.Sprintf("%d 步负", -mate)
+	}
+	return ""
+}
+
+// parseMoves 解析以空白分隔的着法序列，每个着法写作 "row,col"。
+func parseMoves(s string) ([]game.Move, error) {
+	var moves []game.Move
+	for _, tok := range strings.Fields(s) {
+		rs, cs, ok := strings.Cut(tok, ",")
+		r, err1 := strconv.Atoi(rs)
+		c, err2 := strconv.Atoi(cs)
+		if !ok || err1 != nil || err2 != nil || r < 0 || r > 3 || c < 0 || c > 3 {
+			return nil, fmt.Errorf("无法解析 %q，应为 row,col（0–3）", tok)
+		}
+		moves = append(moves, game.Move{Row: r, Col: c})
+	}
+	return moves, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/match"
+)
+
+// runBench 实现 `tracklogicchess bench`：在一组固定局面上以固定深度搜索，
+// 比较各种着法排序访问的结点数、耗时与 β 剪枝统计。
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	depth := fs.Int("depth", 7, "每个局面的搜索深度")
+	count := fs.Int("n", 16, "随机局面数（四种旋转组合轮流使用）")
+	plies := fs.Int("plies", 3, "随机局面从空棋盘走出的步数")
+	openings := fs.String("positions", "", "局面文件，每行一个局面记法（设置后忽略 -n 与 -plies）")
+	evalFlag := fs.String("eval", "lines", "叶结点评估：lines | rotation")
+	orderFlag := fs.String("order", "", "只测试一种排序：full | tt（默认两种都测并比较）")
+	seed := fs.Int64("seed", 1, "随机局面与搜索的随机种子")
+	_ = fs.Parse(args)
+
+	eval, err := game.ParseEval(*evalFlag)
+	if err != nil {
+		fmt.Println("eval 参数无效：", err)
+		return
+	}
+	orders := []game.Ordering{game.OrderTT, game.OrderFull}
+	if *orderFlag != "" {
+		o, err := game.ParseOrdering(*orderFlag)
+		if err != nil {
+			fmt.Println("order 参数无效：", err)
+			return
+		}
+		orders = []game.Ordering{o}
+	}
+	var positions []*game.GameState
+	if *openings != "" {
+		if positions, err = loadOpenings(*openings); err != nil {
+			fmt.Println("读取局面文件失败：", err)
+			return
+		}
+	} else if positions, err = benchPositions(*count, *plies, *seed); err != nil {
+		fmt.Println("plies 参数无效：", err)
+		return
+	}
+
+	fmt.Printf("%d 个局面，深度 %d，评估 %v\n\n", len(positions), *depth, eval)
+	fmt.Printf("%-6s %12s %10s %12s %8s %10s\n", "order", "nodes", "time", "nodes/s", "tt-hit", "first-cut")
+	var base int64
+	for _, o := range orders {
+		var st game.SearchStats
+		start := time.Now()
+		for _, g := range positions {
+			s := game.NewSearcherWith(game.Options{Rand: rand.New(rand.NewSource(*seed)), Eval: eval, Order: o})
+			res := s.Search(context.Background(), g, game.Limits{Depth: *depth}, nil)
+			st.Nodes += res.Stats.Nodes
+			st.TTProbes += res.Stats.TTProbes
+			st.TTHits += res.Stats.TTHits
+			st.Cutoffs += res.Stats.Cutoffs
+			st.FirstCuts += res.Stats.FirstCuts
+		}
+		elapsed := time.Since(start)
+		fmt.Printf("%-6v %12d %10v %12.0f %7.1f%% %9.1f%%", o, st.Nodes, elapsed.Round(time.Millisecond),
+			float64(st.Nodes)/elapsed.Seconds(), 100*st.HitRate(), 100*st.FirstCutRate())
+		if base > 0 {
+			fmt.Printf("  结点数为 %s 的 %.1f%%", orders[0], 100*float64(st.Nodes)/float64(base))
+		} else {
+			base = st.Nodes
+		}
+		fmt.Println()
+	}
+}
+
+// benchPositions 返回 n 个从空棋盘随机走 plies 步得到的未结束局面，四种旋转组合轮流使用。
+func benchPositions(n, plies int, seed int64) ([]*game.GameState, error) {
+	rng := rand.New(rand.NewSource(seed))
+	out := make([]*game.GameState, n)
+	for i := range out {
+		rot := match.Rotations[i%len(match.Rotations)]
+		g, err := match.RandomOpening(rot[0], rot[1], plies, rng)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = g
+	}
+	return out, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"trackLogicChess/internal/book"
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+	"trackLogicChess/internal/tablebase"
+)
+
+// runBook 实现 `tracklogicchess book`：由深度搜索或求解结果建开局库；
+// 指定 -show 时改为打印已有开局库中的局面。
+func runBook(args []string) {
+	fs := flag.NewFlagSet("book", flag.ExitOnError)
+	outPath := fs.String("out", "book.json", "输出的开局库文件")
+	source := fs.String("source", "search", "候选着法来源：search（深度搜索）| solver（求解器的最优着法）")
+	plies := fs.Int("plies", 6, "收入距初始局面不足该步数的局面")
+	wide := fs.Int("wide", 2, "前若干步展开全部合法着法（覆盖对手的各种应着），之后只沿库内着法展开")
+	depth := fs.Int("depth", 8, "search：每个局面的搜索深度")
+	moveTime := fs.Duration("movetime", 0, "search：每个局面的分析时间上限（0 表示不限）")
+	margin := fs.Int("margin", 10, "search：收入与最佳着法分数相差不超过该值的着法")
+	evalFlag := fs.String("eval", "lines", "search：叶结点评估 lines | rotation")
+	tbDir := fs.String("tb", "", "solver：表库目录（缺少时当场求解）")
+	show := fs.String("show", "", "打印该开局库文件的内容，而不是建库")
+	position := fs.String("position", "", "与 -show 一起使用：只打印该局面的条目")
+	_ = fs.Parse(args)
+
+	if *show != "" {
+		showBook(*show, *position)
+		return
+	}
+
+	var src book.Source
+	switch *source {
+	case "search":
+		eval, err := game.ParseEval(*evalFlag)
+		if err != nil {
+			fmt.Println("eval 参数无效：", err)
+			return
+		}
+		src = book.SearchSource{Depth: *depth, MoveTime: *moveTime, Margin: *margin, Options: game.Options{TTBits: 20, Eval: eval}}
+	case "solver":
+		tables := solverTables(*tbDir)
+		defer tables.close()
+		src = book.SolverSource{Table: tables.get}
+	default:
+		fmt.Println("source 参数无效，只能是 search 或 solver。")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	start := time.Now()
+	b, err := book.Build(ctx, src, book.BuildOptions{
+		Plies: *plies,
+		Wide:  *wide,
+		Progress: func(done, queued int) {
+			fmt.Printf("\r已处理 %d 个局面，待处理 %d 个", done, queued)
+		},
+	})
+	fmt.Println()
+	if err != nil {
+		fmt.Println("建库中止：", err)
+		if b.Len() == 0 {
+			return
+		}
+	}
+	if err := b.Save(*outPath); err != nil {
+		fmt.Println("保存开局库失败：", err)
+		return
+	}
+	fmt.Printf("开局库已写入 %s：%d 个局面，用时 %v。\n", *outPath, b.Len(), time.Since(start).Round(time.Millisecond))
+}
+
+// tableCache 按旋转配置缓存求解表：优先从表库目录读取，否则当场求解
+type tableCache struct {
+	dir    string
+	tables map[[2]game.Direction]*solver.Table
+	files  []*tablebase.File
+}
+
+func solverTables(dir string) *tableCache {
+	return &tableCache{dir: dir, tables: make(map[[2]game.Direction]*solver.Table)}
+}
+
+func (c *tableCache) get(dirOuter, dirInner game.Direction) (*solver.Table, error) {
+	key := [2]game.Direction{dirOuter, dirInner}
+	if t, ok := c.tables[key]; ok {
+		return t, nil
+	}
+	var t *solver.Table
+	if c.dir != "" {
+		f, err := tablebase.OpenDir(c.dir, dirOuter, dirInner)
+		if err != nil {
+			return nil, err
+		}
+		c.files = append(c.files, f)
+		t = f.Table
+	} else {
+		fmt.Printf("\n正在求解：外圈%s，内圈%s ...\n", directionString(dirOuter), directionString(dirInner))
+		t = solver.Solve(dirOuter, dirInner)
+	}
+	c.tables[key] = t
+	return t, nil
+}
+
+func (c *tableCache) close() {
+	for _, f := range c.files {
+		f.Close()
+	}
+}
+
+// showBook 打印开局库：指定 position 时只打印该局面的条目，否则打印全部局面
+func showBook(path, position string) {
+	b, err := book.Load(path)
+	if err != nil {
+		fmt.Println("读取开局库失败：", err)
+		return
+	}
+	fmt.Printf("%s：%d 个局面（%s）\n", path, b.Len(), b.Source)
+	positions := b.Positions()
+	if position != "" {
+		positions = []string{position}
+	}
+	for _, pos := range positions {
+		g, err := game.ParsePosition(pos)
+		if err != nil {
+			fmt.Println("position 参数无效：", err)
+			return
+		}
+		entries := b.Lookup(g)
+		if entries == nil {
+			fmt.Printf("\n%s：不在库中\n", pos)
+			continue
+		}
+		fmt.Printf("\n%s\n", pos)
+		for _, e := range entries {
+			fmt.Printf("  %v  权重 %3d  分数 %8d\n", e.Move, e.Weight, e.Score)
+		}
+	}
+}
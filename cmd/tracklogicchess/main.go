@@ -2,65 +2,202 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"trackLogicChess/internal/book"
 	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/mcts"
 	"trackLogicChess/internal/player"
+	"trackLogicChess/internal/record"
 	ui "trackLogicChess/internal/ui/gui"
 )
 
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "solve":
+			runSolve(os.Args[2:])
+			return
+		case "analyze":
+			runAnalyze(os.Args[2:])
+			return
+		case "match":
+			runMatch(os.Args[2:])
+			return
+		case "tournament":
+			runTournament(os.Args[2:])
+			return
+		case "tune":
+			runTune(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "book":
+			runBook(os.Args[2:])
+			return
+		}
+	}
+
 	// 启动参数
 	outerFlag := flag.Int("outer", 0, "外圈旋转方向（0=顺时针,1=逆时针）")
 	innerFlag := flag.Int("inner", 0, "内圈旋转方向（0=顺时针,1=逆时针）")
 	useAI := flag.Bool("ai", true, "是否启用 AI 对手（AI 执 White）")
 	uiMode := flag.String("ui", "terminal", "terminal | gui")
+	depth := flag.Int("depth", 6, "AI 最大搜索深度（0 表示不限）")
+	moveTime := flag.Duration("movetime", 0, "AI 每步思考时间上限，例如 2s（0 表示不限）")
+	tbDir := flag.String("tb", "", "表库目录；设置后 AI 查表完美对弈（缺少文件时退回搜索）")
+	threads := flag.Int("threads", 1, "alphabeta 引擎的搜索线程数")
+	engine := flag.String("engine", "alphabeta", "AI 引擎：alphabeta | mcts")
+	iterations := flag.Int("iterations", 0, "mcts 引擎每步的迭代次数（0 表示按 -movetime，二者都为 0 时取默认值）")
+	uct := flag.Float64("uct", mcts.DefaultExploration, "mcts 引擎的 UCT 探索常数")
+	level := flag.String("level", "", "AI 难度：beginner | easy | medium | hard | expert | perfect（设置后忽略 -engine 与 -depth）")
+	position := flag.String("position", "", "起始局面记法，例如 \"4/1b2/4/4 w cw ccw -\"（旋转方向以记法为准）")
+	recordPath := flag.String("record", "", "终端模式下把对局记录保存到该文件（扩展名 .json 时为 JSON 格式）")
+	seed := flag.Int64("seed", 0, "AI 随机种子（0 表示按当前时间，每局不同）")
+	blackFlag := flag.String("black", "human", "Black 一方：human | ai | 引擎说明，例如 mcts:iterations=5000、hard、alphabeta:depth=4")
+	whiteFlag := flag.String("white", "", "White 一方，写法同 -black（默认由 -ai 决定：ai 或 human）")
+	evalFlag := flag.String("eval", "lines", "AI 叶结点评估：lines（直线计数）| rotation（旋转感知）")
+	orderFlag := flag.String("order", "full", "alphabeta 搜索的着法排序：full（完整排序）| tt（只用置换表着法）")
+	bookFlag := flag.String("book", "", "开局库文件（由 book 子命令生成）；局面在库中时 AI 按权重随机选用库内着法")
+	weights := flag.String("weights", "", "评估权重文件（由 tune 子命令生成），替换默认的直线分值")
+	delay := flag.Duration("delay", 0, "AI 每步落子前至少等待的时间，便于观看 AI 对战，例如 1s")
 	flag.Parse()
 
-	// 参数校验
-	if *outerFlag != 0 && *outerFlag != 1 {
-		fmt.Println("outer 参数无效，只能是 0（顺时针）或 1（逆时针）。")
-		return
+	if *weights != "" {
+		w, err := game.LoadWeights(*weights)
+		if err != nil {
+			fmt.Println("读取权重失败：", err)
+			return
+		}
+		game.SetDefaultWeights(w)
 	}
-	if *innerFlag != 0 && *innerFlag != 1 {
-		fmt.Println("inner 参数无效，只能是 0（顺时针）或 1（逆时针）。")
+
+	// 创建游戏状态
+	gState, ok := startState(*position, *outerFlag, *innerFlag)
+	if !ok {
 		return
 	}
 
-	// 转换为 Direction
-	var dirOuter, dirInner game.Direction
-	if *outerFlag == 0 {
-		dirOuter = game.Clockwise
-	} else {
-		dirOuter = game.CounterClockwise
+	// AI 默认设置：6 层 Alpha-Beta 搜索，可换用 MCTS；指定难度时按难度，指定表库时查表
+	base := engineSpec{
+		Engine:     *engine,
+		Depth:      *depth,
+		MoveTime:   *moveTime,
+		Threads:    *threads,
+		Iterations: *iterations,
+		UCT:        *uct,
+		TBDir:      *tbDir,
+	}
+	var err error
+	if base.Eval, err = game.ParseEval(*evalFlag); err != nil {
+		fmt.Println("eval 参数无效：", err)
+		return
+	}
+	if base.Order, err = game.ParseOrdering(*orderFlag); err != nil {
+		fmt.Println("order 参数无效：", err)
+		return
+	}
+	if *bookFlag != "" {
+		if base.book, err = book.Load(*bookFlag); err != nil {
+			fmt.Println("读取开局库失败：", err)
+			return
+		}
+		base.Book = *bookFlag
 	}
-	if *innerFlag == 0 {
-		dirInner = game.Clockwise
-	} else {
-		dirInner = game.CounterClockwise
+	if *engine != "alphabeta" && *engine != "mcts" {
+		fmt.Println("engine 参数无效，只能是 alphabeta 或 mcts。")
+		return
+	}
+	if *level != "" {
+		lv, err := game.ParseLevel(*level)
+		if err != nil {
+			fmt.Println("level 参数无效：", err)
+			return
+		}
+		base.Engine, base.Level = "level", lv
+	}
+	if *tbDir != "" {
+		base.Engine = "tb"
 	}
 
-	// 创建游戏状态
-	gState := game.NewGame(dirOuter, dirInner)
+	// 双方：-white 未指定时沿用 -ai 的含义
+	if *whiteFlag == "" {
+		*whiteFlag = "human"
+		if *useAI {
+			*whiteFlag = "ai"
+		}
+	}
+	rng := newRand(*seed)
+	var sides [2]side
+	for i, arg := range []string{*blackFlag, *whiteFlag} {
+		sd, err := newSide(arg, base, rng, *delay)
+		if err != nil {
+			fmt.Printf("%s 参数无效：%v\n", []string{"black", "white"}[i], err)
+			return
+		}
+		defer sd.close()
+		sides[i] = sd
+	}
 
 	// 根据 ui 参数选择运行模式
 	switch *uiMode {
+	case "gui":
+		launchGUI(gState, sides[0].player, sides[1].player)
 	case "terminal":
-		launchGUI(gState, *useAI)
+		runTerminalLoop(gState, sides, *recordPath)
 	default:
-		runTerminalLoop(gState, *useAI)
+		fmt.Println("ui 参数无效，只能是 terminal 或 gui。")
+	}
+}
+
+// side 为一方的设置；player 为 nil 表示人类（由终端输入或鼠标落子）
+type side struct {
+	name   string
+	player game.Player
+	close  func()
+}
+
+// newSide 解析 -black / -white 参数：human、ai 或引擎说明。
+func newSide(arg string, base engineSpec, rng *rand.Rand, delay time.Duration) (side, error) {
+	if arg == "human" {
+		return side{name: "human", close: func() {}}, nil
+	}
+	spec, err := parseEngineSpec(arg, base)
+	if err != nil {
+		return side{}, err
 	}
+	p, close := spec.newPlayer(rng, true)
+	return side{name: spec.String(), player: delayed(p, delay), close: close}, nil
 }
 
-// launchGUI 以 Ebiten 窗口模式启动游戏
-func launchGUI(gs *game.GameState, ai bool) {
-	app := ui.NewApp(gs, ai)
+// newRand 返回 AI 使用的随机源：seed 为 0 时以当前时间为种子。
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// launchGUI 以 Ebiten 窗口模式启动游戏；black / white 为 nil 的一方由鼠标落子。
+func launchGUI(gs *game.GameState, black, white game.Player) {
+	if black == nil {
+		black = ui.NewHuman()
+	}
+	if white == nil {
+		white = ui.NewHuman()
+	}
+	app := ui.NewApp(gs, black, white)
 	ebiten.SetWindowTitle("Track Logic Chess")
 	ebiten.SetWindowResizable(false)
 
@@ -70,68 +207,137 @@ func launchGUI(gs *game.GameState, ai bool) {
 	}
 }
 
-// runTerminalLoop 原生命令行模式
-func runTerminalLoop(g *game.GameState, useAI bool) {
+// runTerminalLoop 原生命令行模式；player 为 nil 的一方由终端输入落子，
+// recPath 非空时在退出前保存对局记录。
+// 人类回合可输入 undo / redo 悔棋与重做、hint 查看战术提示；人机对战时一次悔棋同时撤销 AI 的应着。
+func runTerminalLoop(g *game.GameState, sides [2]side, recPath string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	humans := 0
+	var players [2]game.Player
+	var terminals []*terminalHuman
+	for i, sd := range sides {
+		players[i] = sd.player
+		if sd.player == nil {
+			h := &terminalHuman{scanner: scanner}
+			terminals = append(terminals, h)
+			players[i] = h
+			humans++
+		}
+	}
+
+	rec := record.New(g, sides[0].name, sides[1].name)
+	if recPath != "" {
+		defer saveRecord(rec, recPath)
+	}
+	d := game.NewDriver(g, players[0], players[1])
+	for _, h := range terminals {
+		h.session = d.Session
+	}
+	d.OnMove = func(mv game.Move, g *game.GameState) {
+		rec.Add(mv, g)
+		mover := g.CurrentPlayer
+		if !g.IsGameOver() {
+			mover = mover.Opponent()
+		}
+		if _, ok := d.PlayerFor(mover).(*terminalHuman); !ok {
+			fmt.Printf("AI (%s) 在 (%d,%d) 下棋。\n", mover.String(), mv.Row, mv.Col)
+		}
+		printBoard(g)
+	}
+	d.OnUndo = func(mv game.Move, g *game.GameState) {
+		rec.Undo(g)
+		fmt.Printf("撤销 (%d,%d)。\n", mv.Row, mv.Col)
+		if _, ok := d.PlayerFor(g.CurrentPlayer).(*terminalHuman); ok {
+			printBoard(g)
+		}
+	}
+
 	fmt.Println("=== Track Logic Chess (4×4 旋转棋) ===")
 	fmt.Printf("外圈旋转：%s，内圈旋转：%s。\n",
 		directionString(g.DirOuter), directionString(g.DirInner))
-	if useAI {
-		fmt.Println("已启用 AI 对手 (AI 执 White)。")
-	} else {
+	switch humans {
+	case 2:
 		fmt.Println("人人对战模式。")
+	default:
+		fmt.Printf("Black：%s，White：%s。\n", sides[0].name, sides[1].name)
+	}
+	if humans > 0 {
+		fmt.Println("人类玩家请输入：row col （0–3），undo / redo 悔棋与重做，或 hint 查看战术提示")
 	}
-	fmt.Println("人类玩家请输入：row col （0–3）")
-	fmt.Println()
-	fmt.Println("当前棋盘：")
-	fmt.Println(g.Board.String())
 	fmt.Println()
+	printBoard(g)
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for !g.IsGameOver() {
-		current := g.CurrentPlayer
-		// AI 回合
-		if useAI && current == player.White {
-			fmt.Println("AI 正在思考...")
-			mv := game.FindBestMoveDeep(g, 6)
-			_ = g.ApplyMove(mv.Row, mv.Col)
-			fmt.Printf("AI 在 (%d,%d) 下棋。\n", mv.Row, mv.Col)
-		} else {
-			// 人类回合
-			fmt.Printf("轮到玩家 %s，请输入 (row col)：", current.String())
-			if !scanner.Scan() {
+	for {
+		if _, err := d.Run(context.Background()); err != nil {
+			if errors.Is(err, errInputClosed) {
 				fmt.Println("\n读取输入失败，程序退出。")
-				return
-			}
-			line := strings.TrimSpace(scanner.Text())
-			parts := strings.Fields(line)
-			if len(parts) != 2 {
-				fmt.Println("输入格式错误，请输入 2 个数字，例如：1 2")
-				continue
-			}
-			r, err1 := strconv.Atoi(parts[0])
-			c, err2 := strconv.Atoi(parts[1])
-			if err1 != nil || err2 != nil || r < 0 || r > 3 || c < 0 || c > 3 {
-				fmt.Println("坐标必须在 0–3 之间，请重试。")
-				continue
-			}
-			if err := g.ApplyMove(r, c); err != nil {
-				fmt.Println("操作无效：", err)
-				continue
+			} else {
+				fmt.Println("对局中止：", err)
 			}
+			return
+		}
+		// 结束判定
+		if winner := g.WinnerColor(); winner == player.Empty {
+			fmt.Println("棋盘已满，平局结束。")
+		} else {
+			fmt.Printf("游戏结束！玩家 %s 获胜。\n", winner.String())
+		}
+		if humans == 0 {
+			return
 		}
+		fmt.Print("输入 undo 悔棋，直接回车退出：")
+		if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "undo" {
+			return
+		}
+		d.Undo()
+	}
+}
+
+// printBoard 打印当前棋盘
+func printBoard(g *game.GameState) {
+	fmt.Println("\n当前棋盘：")
+	fmt.Println(g.Board.String())
+	fmt.Println()
+}
 
-		// 显示最新棋盘
-		fmt.Println("\n落子 + 旋转 后的棋盘：")
-		fmt.Println(g.Board.String())
-		fmt.Println()
+// saveRecord 保存对局记录并打印结果。
+func saveRecord(rec *record.Record, path string) {
+	if err := rec.Save(path); err != nil {
+		fmt.Println("保存对局记录失败：", err)
+		return
 	}
+	fmt.Println("对局记录已保存到", path)
+}
 
-	// 结束判定
-	if winner := g.WinnerColor(); winner == player.Empty {
-		fmt.Println("棋盘已满，平局结束。")
-	} else {
-		fmt.Printf("游戏结束！玩家 %s 获胜。\n", winner.String())
+// parseDirections 校验 -outer / -inner 参数并转换为 Direction，无效时打印提示并返回 false。
+func parseDirections(outer, inner int) (dirOuter, dirInner game.Direction, ok bool) {
+	if outer != 0 && outer != 1 {
+		fmt.Println("outer 参数无效，只能是 0（顺时针）或 1（逆时针）。")
+		return
+	}
+	if inner != 0 && inner != 1 {
+		fmt.Println("inner 参数无效，只能是 0（顺时针）或 1（逆时针）。")
+		return
+	}
+	return game.Direction(outer), game.Direction(inner), true
+}
+
+// startState 返回对局起点：指定了局面记法时解析之（旋转方向以记法为准），
+// 否则按 -outer / -inner 创建初始局面。参数无效时打印提示并返回 false。
+func startState(position string, outer, inner int) (*game.GameState, bool) {
+	if position != "" {
+		g, err := game.ParsePosition(position)
+		if err != nil {
+			fmt.Println("position 参数无效：", err)
+			return nil, false
+		}
+		return g, true
+	}
+	dirOuter, dirInner, ok := parseDirections(outer, inner)
+	if !ok {
+		return nil, false
 	}
+	return game.NewGame(dirOuter, dirInner), true
 }
 
 // directionString 将 Direction 转为中文
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/match"
+	"trackLogicChess/internal/mcts"
+)
+
+// runMatch 实现 `tracklogicchess match`：两个引擎配置对弈多局，统计战绩、Elo 差与 SPRT 结论。
+func runMatch(args []string) {
+	fs := flag.NewFlagSet("match", flag.ExitOnError)
+	aFlag := fs.String("a", "ai", "A 方引擎说明（写法同 -black），例如 alphabeta:depth=6")
+	bFlag := fs.String("b", "mcts", "B 方引擎说明")
+	games := fs.Int("games", 100, "对局数（奇数时向上取偶，每个开局双方各执黑一次）")
+	concurrency := fs.Int("concurrency", runtime.NumCPU(), "同时进行的对局数")
+	plies := fs.Int("plies", 2, "随机开局的步数（未指定 -openings 时）")
+	openings := fs.String("openings", "", "开局文件：每行一个局面记法，# 开头为注释")
+	seed := fs.Int64("seed", 0, "随机种子（0 表示按当前时间）")
+	sprt := fs.String("sprt", "", "SPRT 的 elo0,elo1，例如 0,10；设置后得出结论即停止")
+	alpha := fs.Float64("alpha", 0.05, "SPRT 第一类错误率")
+	beta := fs.Float64("beta", 0.05, "SPRT 第二类错误率")
+	outDir := fs.String("out", "", "若指定目录，则把每局的对局记录写入该目录")
+	depth := fs.Int("depth", 6, "引擎说明未写 depth 时的搜索深度")
+	moveTime := fs.Duration("movetime", 0, "引擎说明未写 movetime 时的每步时间")
+	iterations := fs.Int("iterations", 0, "引擎说明未写 iterations 时的 mcts 迭代次数")
+	uct := fs.Float64("uct", mcts.DefaultExploration, "引擎说明未写 uct 时的 UCT 探索常数")
+	_ = fs.Parse(args)
+
+	if *plies < 0 || *plies > match.MaxRandomPlies {
+		fmt.Printf("plies 参数无效，应在 0 到 %d 之间。\n", match.MaxRandomPlies)
+		return
+	}
+	base := engineSpec{
+		Engine:     "alphabeta",
+		Depth:      *depth,
+		MoveTime:   *moveTime,
+		Threads:    1,
+		Iterations: *iterations,
+		UCT:        *uct,
+	}
+	cfg := match.Config{
+		Games:       *games,
+		Concurrency: *concurrency,
+		RandomPlies: *plies,
+		Seed:        *seed,
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	for _, side := range []struct {
+		name, arg string
+		engine    *match.Engine
+	}{{"a", *aFlag, &cfg.A}, {"b", *bFlag, &cfg.B}} {
+		spec, err := parseEngineSpec(side.arg, base)
+		if err != nil {
+			fmt.Printf("%s 参数无效：%v\n", side.name, err)
+			return
+		}
+		*side.engine = match.Engine{Name: spec.String(), New: func(rng *rand.Rand) game.Player {
+			p, _ := spec.newPlayer(rng, false)
+			return p
+		}}
+	}
+	if *openings != "" {
+		var err error
+		if cfg.Openings, err = loadOpenings(*openings); err != nil {
+			fmt.Println("读取开局文件失败：", err)
+			return
+		}
+	}
+	if *sprt != "" {
+		elo0, elo1, ok := parseSPRT(*sprt)
+		if !ok {
+			fmt.Println("sprt 参数无效，应为 elo0,elo1，例如 0,10。")
+			return
+		}
+		cfg.SPRT = &match.SPRT{Elo0: elo0, Elo1: elo1, Alpha: *alpha, Beta: *beta}
+	}
+	if *outDir != "" {
+		if err := os.MkdirAll(*outDir, 0o755); err != nil {
+			fmt.Println("创建目录失败：", err)
+			return
+		}
+	}
+
+	fmt.Printf("A：%s\nB：%s\n", cfg.A.Name, cfg.B.Name)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	start := time.Now()
+	sum, err := match.Run(ctx, cfg, func(g match.Game, sum match.Summary) {
+		fmt.Printf("对局 %3d  A 执%s%s  %s\n", g.Index+1,
+			colorName(g.AIsBlack), pointsName(g.PointsA()), summaryString(sum, cfg.SPRT))
+		if *outDir != "" {
+			path := filepath.Join(*outDir, fmt.Sprintf("game-%04d.txt", g.Index+1))
+			if err := g.Record.Save(path); err != nil {
+				fmt.Println("保存对局记录失败：", err)
+			}
+		}
+	})
+	if err != nil {
+		fmt.Println("比赛中止：", err)
+	}
+	fmt.Printf("\n共 %d 局，用时 %v。\n", sum.Score.Games(), time.Since(start).Round(time.Millisecond))
+	fmt.Println(summaryString(sum, cfg.SPRT))
+	if cfg.SPRT != nil {
+		switch sum.Decision {
+		case match.AcceptH1:
+			fmt.Printf("SPRT：接受 H1，A 至少强 %g Elo。\n", cfg.SPRT.Elo1)
+		case match.AcceptH0:
+			fmt.Printf("SPRT：接受 H0，A 强不过 %g Elo。\n", cfg.SPRT.Elo0)
+		default:
+			fmt.Println("SPRT：尚无结论。")
+		}
+	}
+}
+
+// summaryString 格式化战绩、Elo 差与（若有）SPRT 进度
+func summaryString(sum match.Summary, sprt *match.SPRT) string {
+	elo, margin := sum.Score.Elo()
+	s := fmt.Sprintf("A：%v  Elo %s ± %s", sum.Score, eloString(elo), eloString(margin))
+	if sprt != nil {
+		lower, upper := sprt.Bounds()
+		s += fmt.Sprintf("  LLR %.2f (%.2f, %.2f)", sum.LLR, lower, upper)
+	}
+	return s
+}
+
+// eloString 格式化 Elo 值，无穷大时写作 inf
+func eloString(v float64) string {
+	if math.IsInf(v, 0) {
+		if v < 0 {
+			return "-inf"
+		}
+		return "inf"
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// colorName 返回执子颜色的中文名
+func colorName(black bool) string {
+	if black {
+		return "黑"
+	}
+	return "白"
+}
+
+// pointsName 返回 A 一局得分对应的胜负
+func pointsName(points float64) string {
+	switch points {
+	case 1:
+		return "胜"
+	case 0:
+		return "负"
+	}
+	return "和"
+}
+
+// parseSPRT 解析 "elo0,elo1"
+func parseSPRT(s string) (elo0, elo1 float64, ok bool) {
+	a, b, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	elo0, err0 := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	elo1, err1 := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	return elo0, elo1, err0 == nil && err1 == nil && elo1 > elo0
+}
+
+// loadOpenings 读取开局文件：每行一个局面记法，空行与 # 开头的行被忽略。
+func loadOpenings(path string) ([]*game.GameState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []*game.GameState
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		g, err := game.ParsePosition(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if g.IsGameOver() {
+			return nil, fmt.Errorf("line %d: game is already over", n)
+		}
+		out = append(out, g)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: no positions", path)
+	}
+	return out, nil
+}
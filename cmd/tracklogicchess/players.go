@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackLogicChess/internal/book"
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/mcts"
+)
+
+// errInputClosed 表示终端输入已关闭（EOF 或读取失败）。
+var errInputClosed = errors.New("input closed")
+
+// alphaBetaPlayer 返回以迭代加深 Alpha-Beta 搜索选着的 Player，搜索器按 opts 创建；
+// verbose 时打印每层的搜索信息。
+func alphaBetaPlayer(opts game.Options, limits game.Limits, verbose bool) game.Player {
+	p := game.NewEnginePlayer(opts, limits)
+	if !verbose {
+		return p
+	}
+	p.Info = func(info game.SearchInfo) {
+		fmt.Printf("  深度 %2d  分数 %8d  结点 %9d  用时 %6v  主变 %v\n",
+			info.Depth, info.Score, info.Nodes, info.Elapsed.Round(time.Millisecond), info.PV)
+	}
+	p.Done = func(res game.SearchResult) {
+		fmt.Printf("搜索结点 %d，置换表命中率 %.1f%%（剪枝 %d 次）\n",
+			res.Stats.Nodes, 100*res.Stats.HitRate(), res.Stats.TTCutoffs)
+		p.Searcher.ResetStats()
+	}
+	return p
+}
+
+// bookPlayer 在 engine 之前查询开局库 b；verbose 时打印选用的库内着法。
+func bookPlayer(b *book.Book, engine game.Player, rng *rand.Rand, verbose bool) game.Player {
+	p := book.NewPlayer(b, engine, rng)
+	if verbose {
+		p.OnHit = func(g *game.GameState, mv game.Move) {
+			fmt.Printf("开局库着法 %v\n", mv)
+		}
+	}
+	return p
+}
+
+// mctsPlayer 返回以 MCTS 选着的 Player，搜索树在相邻两步之间复用；verbose 时打印各候选着法的统计。
+func mctsPlayer(rng *rand.Rand, exploration float64, limits mcts.Limits, verbose bool) game.Player {
+	engine := mcts.New(mcts.Config{Exploration: exploration, Rand: rng})
+	return game.PlayerFunc(func(ctx context.Context, g *game.GameState) (game.Move, error) {
+		start := time.Now()
+		res := engine.Search(ctx, g, limits)
+		if !verbose {
+			return res.Move, nil
+		}
+		for _, st := range res.Moves {
+			fmt.Printf("  %v  访问 %7d  胜率 %5.1f%%\n", st.Move, st.Visits, 100*st.WinRate)
+		}
+		fmt.Printf("迭代 %d 次（沿用 %d 次），用时 %v\n",
+			res.Iterations, res.Reused, time.Since(start).Round(time.Millisecond))
+		return res.Move, nil
+	})
+}
+
+// terminalHuman 从终端读取着法的人类 Player；输入 undo / redo 时请求悔棋与重做，输入 hint 时打印战术提示。
+type terminalHuman struct {
+	scanner *bufio.Scanner
+	session *game.Session // 用于判断能否悔棋 / 重做
+}
+
+// ChooseMove 实现 game.Player：反复提示直到读到合法着法或悔棋请求。
+func (h *terminalHuman) ChooseMove(ctx context.Context, g *game.GameState) (game.Move, error) {
+	for {
+		fmt.Printf("轮到玩家 %s，请输入 (row col)：", g.CurrentPlayer.String())
+		if !h.scanner.Scan() {
+			return game.Move{}, errInputClosed
+		}
+		line := strings.TrimSpace(h.scanner.Text())
+		switch line {
+		case "undo":
+			if !h.session.CanUndo() {
+				fmt.Println("没有可以悔的棋。")
+				continue
+			}
+			return game.Move{}, game.ErrUndo
+		case "redo":
+			if !h.session.CanRedo() {
+				fmt.Println("没有可以重做的棋。")
+				continue
+			}
+			return game.Move{}, game.ErrRedo
+		case "hint":
+			printHints(g.Tactics())
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			fmt.Println("输入格式错误，请输入 2 个数字，例如：1 2")
+			continue
+		}
+		r, err1 := strconv.Atoi(parts[0])
+		c, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil || r < 0 || r > 3 || c < 0 || c > 3 {
+			fmt.Println("坐标必须在 0–3 之间，请重试。")
+			continue
+		}
+		if !g.Board.IsEmpty(r, c) {
+			fmt.Println("操作无效：该位置已有棋子")
+			continue
+		}
+		return game.Move{Row: r, Col: c}, nil
+	}
+}
+
+// IsHuman 实现 game.HumanPlayer。
+func (h *terminalHuman) IsHuman() bool {
+	return true
+}
+
+// printHints 打印走棋方的战术提示：一步取胜、必须堵截、会输掉对局的落子格。
+func printHints(t game.Tactics) {
+	fmt.Printf("  取胜：%v\n  堵截：%v\n  落败：%v\n  送胜：%v\n", t.Wins, t.Blocks, t.Losses, t.Hangs)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+	"trackLogicChess/internal/tablebase"
+)
+
+// runSolve 实现 `tracklogicchess solve`：穷举求解指定旋转配置下的全部可达局面。
+func runSolve(args []string) {
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	outerFlag := fs.Int("outer", 0, "外圈旋转方向（0=顺时针,1=逆时针）")
+	innerFlag := fs.Int("inner", 0, "内圈旋转方向（0=顺时针,1=逆时针）")
+	outDir := fs.String("out", "", "若指定目录，则把结果写成表库文件")
+	position := fs.String("position", "", "求解后查询的局面记法（旋转方向以记法为准），默认为初始局面")
+	_ = fs.Parse(args)
+
+	g, ok := startState(*position, *outerFlag, *innerFlag)
+	if !ok {
+		return
+	}
+	// 互为镜像的两种配置共用一张表，总是求解外圈顺时针的那一种
+	dirOuter, dirInner := game.CanonicalDirections(g.DirOuter, g.DirInner)
+
+	fmt.Printf("正在求解：外圈%s，内圈%s（同时适用于外圈%s，内圈%s）...\n",
+		directionString(dirOuter), directionString(dirInner),
+		directionString(dirOuter.Reverse()), directionString(dirInner.Reverse()))
+	start := time.Now()
+	t := solver.Solve(dirOuter, dirInner)
+	wins, losses, draws := t.Stats()
+	fmt.Printf("完成，用时 %v。\n", time.Since(start).Round(time.Millisecond))
+	fmt.Printf("可达的未终局规范局面：%d（走棋方胜 %d / 负 %d / 和 %d）\n",
+		wins+losses+draws, wins, losses, draws)
+
+	v, best := t.Probe(g)
+	fmt.Printf("局面 %s：走棋方 %s，%d 步后结束。\n", game.FormatPosition(g), v.Outcome(), v.Distance())
+	fmt.Printf("最优着法：%v\n", best)
+
+	if *outDir != "" {
+		path, err := tablebase.Save(*outDir, t)
+		if err != nil {
+			fmt.Println("写入表库失败：", err)
+			return
+		}
+		fmt.Println("表库已写入", path)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackLogicChess/internal/book"
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/mcts"
+	"trackLogicChess/internal/tablebase"
+)
+
+// engineSpec 描述一个 AI 对手。
+// 命令行写法为 name[:key=value,...]：name 为 alphabeta、mcts、tb 或难度名（beginner … perfect），
+// 可用的键有 depth、movetime、threads、iterations、uct、tb、weights、eval、order、book。省略的设置取自全局参数。
+type engineSpec struct {
+	Engine     string // alphabeta | mcts | level | tb
+	Level      game.Level
+	Depth      int
+	MoveTime   time.Duration
+	Threads    int
+	Iterations int
+	UCT        float64
+	TBDir      string
+	Eval       game.Eval     // 叶结点评估
+	Order      game.Ordering // 着法排序
+	Weights    string        // 评估权重文件，空表示默认权重
+	weights    *game.Weights // 由 Weights 读入
+	Book       string        // 开局库文件，空表示不用开局库
+	book       *book.Book    // 由 Book 读入
+}
+
+// parseEngineSpec 解析引擎说明；"ai" 表示 base 本身，"ai:key=value" 在 base 上修改设置。
+func parseEngineSpec(s string, base engineSpec) (engineSpec, error) {
+	spec := base
+	name, opts, _ := strings.Cut(strings.TrimSpace(s), ":")
+	switch name {
+	case "ai":
+	case "alphabeta", "mcts":
+		spec.Engine = name
+	case "tb":
+		spec.Engine = "tb"
+	default:
+		lv, err := game.ParseLevel(name)
+		if err != nil {
+			return spec, fmt.Errorf("unknown engine %q", name)
+		}
+		spec.Engine, spec.Level = "level", lv
+	}
+	if opts == "" {
+		return spec, spec.check()
+	}
+	for _, kv := range strings.Split(opts, ",") {
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok {
+			return spec, fmt.Errorf("option %q: want key=value", kv)
+		}
+		var err error
+		switch key {
+		case "depth":
+			spec.Depth, err = strconv.Atoi(val)
+		case "movetime":
+			spec.MoveTime, err = time.ParseDuration(val)
+		case "threads":
+			spec.Threads, err = strconv.Atoi(val)
+		case "iterations":
+			spec.Iterations, err = strconv.Atoi(val)
+		case "uct":
+			spec.UCT, err = strconv.ParseFloat(val, 64)
+		case "tb":
+			spec.Engine, spec.TBDir = "tb", val
+		case "weights":
+			var w game.Weights
+			w, err = game.LoadWeights(val)
+			spec.Weights, spec.weights = val, &w
+		case "eval":
+			spec.Eval, err = game.ParseEval(val)
+		case "order":
+			spec.Order, err = game.ParseOrdering(val)
+		case "book":
+			spec.Book = val
+			spec.book, err = book.Load(val)
+		default:
+			return spec, fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return spec, fmt.Errorf("option %s: %w", key, err)
+		}
+	}
+	return spec, spec.check()
+}
+
+// check 校验设置的取值范围
+func (e engineSpec) check() error {
+	switch {
+	case e.Depth < 0:
+		return fmt.Errorf("depth must be >= 0")
+	case e.MoveTime < 0:
+		return fmt.Errorf("movetime must be >= 0")
+	case e.Threads < 1:
+		return fmt.Errorf("threads must be >= 1")
+	case e.Iterations < 0:
+		return fmt.Errorf("iterations must be >= 0")
+	case e.Engine == "tb" && e.TBDir == "":
+		return fmt.Errorf("tb needs a table directory")
+	}
+	return nil
+}
+
+// String 返回简短描述，写入对局记录的 Black / White 标签
+func (e engineSpec) String() string {
+	switch e.Engine {
+	case "level":
+		if opts := e.evalString(); opts != "" {
+			return e.Level.String() + ":" + opts[1:]
+		}
+		return e.Level.String()
+	case "mcts":
+		if e.Iterations > 0 {
+			return fmt.Sprintf("mcts:iterations=%d", e.Iterations) + e.bookString()
+		}
+		if e.MoveTime > 0 {
+			return fmt.Sprintf("mcts:movetime=%v", e.MoveTime) + e.bookString()
+		}
+		if b := e.bookString(); b != "" {
+			return "mcts:" + b[1:]
+		}
+		return "mcts"
+	case "tb":
+		return "tb:tb=" + e.TBDir + e.searchString()
+	}
+	return "alphabeta:" + e.searchString()[1:]
+}
+
+// searchString 返回 Alpha-Beta 搜索设置的说明（以逗号开头），表库缺失时的退回搜索同样使用
+func (e engineSpec) searchString() string {
+	s := fmt.Sprintf(",depth=%d", e.Depth)
+	if e.MoveTime > 0 {
+		s += fmt.Sprintf(",movetime=%v", e.MoveTime)
+	}
+	if e.Threads > 1 {
+		s += fmt.Sprintf(",threads=%d", e.Threads)
+	}
+	return s + e.evalString()
+}
+
+// evalString 返回非默认评估与排序设置的说明（以逗号开头），没有时为空串
+func (e engineSpec) evalString() string {
+	s := ""
+	if e.Eval != game.EvalLines {
+		s += ",eval=" + e.Eval.String()
+	}
+	if e.Order != game.OrderFull {
+		s += ",order=" + e.Order.String()
+	}
+	if e.Weights != "" {
+		s += ",weights=" + e.Weights
+	}
+	return s + e.bookString()
+}
+
+// bookString 返回开局库设置的说明（以逗号开头），没有时为空串
+func (e engineSpec) bookString() string {
+	if e.Book == "" {
+		return ""
+	}
+	return ",book=" + e.Book
+}
+
+// options 返回创建搜索器的参数
+func (e engineSpec) options(rng *rand.Rand) game.Options {
+	return game.Options{TTBits: 20, Rand: rng, Eval: e.Eval, Weights: e.weights, Order: e.Order}
+}
+
+// limits 返回 Alpha-Beta 搜索（包括表库缺失时的退回搜索）的预算
+func (e engineSpec) limits() game.Limits {
+	return game.Limits{Depth: e.Depth, MoveTime: e.MoveTime, Threads: e.Threads}
+}
+
+// newPlayer 按说明创建 Player，verbose 时打印搜索信息；返回的 close 释放表库等资源（没有时为空函数）。
+func (e engineSpec) newPlayer(rng *rand.Rand, verbose bool) (p game.Player, close func()) {
+	close = func() {}
+	switch e.Engine {
+	case "mcts":
+		p = mctsPlayer(rng, e.UCT, mcts.Limits{Iterations: e.Iterations, MoveTime: e.MoveTime}, verbose)
+	case "level":
+		p = game.NewLevelPlayer(e.options(rng), e.Level)
+	case "tb":
+		tb := tablebase.NewPlayer(e.TBDir, alphaBetaPlayer(e.options(rng), e.limits(), verbose))
+		p, close = tb, func() { tb.Close() }
+	default:
+		p = alphaBetaPlayer(e.options(rng), e.limits(), verbose)
+	}
+	if e.book != nil {
+		p = bookPlayer(e.book, p, rng, verbose)
+	}
+	return p, close
+}
+
+// delayed 让 p 在每步落子前至少等待 d，便于观看 AI 对战。
+func delayed(p game.Player, d time.Duration) game.Player {
+	if d <= 0 {
+		return p
+	}
+	return game.PlayerFunc(func(ctx context.Context, g *game.GameState) (game.Move, error) {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		mv, err := p.ChooseMove(ctx, g)
+		if err != nil {
+			return mv, err
+		}
+		select {
+		case <-timer.C:
+			return mv, nil
+		case <-ctx.Done():
+			return mv, ctx.Err()
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"trackLogicChess/internal/game"
+)
+
+// testBase 模拟全局参数给出的默认设置
+var testBase = engineSpec{Engine: "alphabeta", Depth: 6, Threads: 1, UCT: 1.4}
+
+func TestParseEngineSpec(t *testing.T) {
+	with := func(f func(e *engineSpec)) engineSpec {
+		e := testBase
+		f(&e)
+		return e
+	}
+	cases := []struct {
+		in   string
+		want engineSpec
+		str  string
+	}{
+		{"ai", testBase, "alphabeta:depth=6"},
+		{" ai ", testBase, "alphabeta:depth=6"},
+		{"ai:depth=8", with(func(e *engineSpec) { e.Depth = 8 }), "alphabeta:depth=8"},
+		{"alphabeta:depth=4,movetime=1s,threads=2", with(func(e *engineSpec) {
+			e.Depth, e.MoveTime, e.Threads = 4, time.Second, 2
+		}), "alphabeta:depth=4,movetime=1s,threads=2"},
+		{"alphabeta:eval=rotation,order=tt", with(func(e *engineSpec) {
+			e.Eval, e.Order = game.EvalRotation, game.OrderTT
+		}), "alphabeta:depth=6,eval=rotation,order=tt"},
+		{"mcts", with(func(e *engineSpec) { e.Engine = "mcts" }), "mcts"},
+		{"mcts:iterations=5000,uct=2", with(func(e *engineSpec) {
+			e.Engine, e.Iterations, e.UCT = "mcts", 5000, 2
+		}), "mcts:iterations=5000"},
+		{"mcts:movetime=500ms", with(func(e *engineSpec) {
+			e.Engine, e.MoveTime = "mcts", 500*time.Millisecond
+		}), "mcts:movetime=500ms"},
+		{"hard", with(func(e *engineSpec) { e.Engine, e.Level = "level", game.Hard }), "hard"},
+		{"Beginner:eval=rotation", with(func(e *engineSpec) {
+			e.Engine, e.Level, e.Eval = "level", game.Beginner, game.EvalRotation
+		}), "beginner:eval=rotation"},
+		{"tb:tb=tables", with(func(e *engineSpec) { e.Engine, e.TBDir = "tb", "tables" }), "tb:tb=tables,depth=6"},
+		{"alphabeta:tb=tables", with(func(e *engineSpec) { e.Engine, e.TBDir = "tb", "tables" }), "tb:tb=tables,depth=6"},
+		{"tb:tb=tables,depth=4,movetime=1s,order=tt", with(func(e *engineSpec) {
+			e.Engine, e.TBDir, e.Depth, e.MoveTime, e.Order = "tb", "tables", 4, time.Second, game.OrderTT
+		}), "tb:tb=tables,depth=4,movetime=1s,order=tt"},
+	}
+	for _, c := range cases {
+		got, err := parseEngineSpec(c.in, testBase)
+		if err != nil {
+			t.Fatalf("parseEngineSpec(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("parseEngineSpec(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+		if s := got.String(); s != c.str {
+			t.Fatalf("parseEngineSpec(%q).String() = %q, want %q", c.in, s, c.str)
+		}
+		// String 的结果可以再次解析，且描述不变
+		again, err := parseEngineSpec(c.str, testBase)
+		if err != nil || again.String() != c.str {
+			t.Fatalf("parseEngineSpec(%q) = %v, %v", c.str, again, err)
+		}
+	}
+}
+
+func TestParseEngineSpecErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"stockfish",
+		"ai:depth",
+		"ai:depth=x",
+		"ai:depth=-1",
+		"ai:threads=0",
+		"ai:movetime=1",
+		"ai:movetime=-1s",
+		"mcts:iterations=-5",
+		"ai:uct=high",
+		"ai:color=red",
+		"ai:eval=fancy",
+		"ai:order=best",
+		"tb",
+		"ai:weights=no-such-file.json",
+		"ai:book=no-such-file.json",
+	} {
+		if spec, err := parseEngineSpec(in, testBase); err == nil {
+			t.Fatalf("parseEngineSpec(%q) = %+v, want an error", in, spec)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/mcts"
+	"trackLogicChess/internal/tournament"
+)
+
+// runTournament 实现 `tracklogicchess tournament`：按配置文件进行循环赛或瑞士制比赛，可断点续赛。
+func runTournament(args []string) {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	configPath := fs.String("config", "", "赛事配置文件（JSON）；续赛时可省略")
+	outDir := fs.String("out", "", "赛事目录：保存进度、成绩表与对局记录（必填）")
+	concurrency := fs.Int("concurrency", 0, "同时进行的对局数（0 表示按配置文件）")
+	_ = fs.Parse(args)
+
+	if *outDir == "" {
+		fmt.Println("请用 -out 指定赛事目录。")
+		return
+	}
+	var cfg *tournament.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = tournament.LoadConfig(*configPath); err != nil {
+			fmt.Println("读取配置失败：", err)
+			return
+		}
+		if *concurrency > 0 {
+			cfg.Concurrency = *concurrency
+		}
+	}
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Println("创建目录失败：", err)
+		return
+	}
+
+	base := engineSpec{Engine: "alphabeta", Depth: 6, Threads: 1, UCT: mcts.DefaultExploration}
+	tour, err := tournament.Open(*outDir, cfg, func(s string) (func(*rand.Rand) game.Player, error) {
+		spec, err := parseEngineSpec(s, base)
+		if err != nil {
+			return nil, err
+		}
+		return func(rng *rand.Rand) game.Player {
+			p, _ := spec.newPlayer(rng, false)
+			return p
+		}, nil
+	})
+	switch {
+	case errors.Is(err, tournament.ErrNoState):
+		fmt.Println("该目录中没有赛事，请用 -config 指定配置文件。")
+		return
+	case err != nil:
+		fmt.Println("打开赛事失败：", err)
+		return
+	}
+
+	engines := tour.Config().Engines
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = tour.Run(ctx, func(p tournament.Pairing) {
+		fmt.Printf("第 %d 轮 第 %d 台  %s - %s  %s\n", p.Round, p.Board,
+			engines[p.Black].Name, engines[p.White].Name, p.Result)
+	})
+	fmt.Println()
+	_ = tour.WriteCrosstable(os.Stdout)
+	switch {
+	case errors.Is(err, context.Canceled):
+		fmt.Printf("\n比赛已中断，进度已保存；运行 tracklogicchess tournament -out %s 继续。\n", *outDir)
+	case err != nil:
+		fmt.Println("\n比赛中止：", err)
+	default:
+		fmt.Println("\n比赛结束，成绩表与对局记录保存在", *outDir)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/record"
+	"trackLogicChess/internal/solver"
+	"trackLogicChess/internal/tune"
+)
+
+// runTune 实现 `tracklogicchess tune`：从自对弈、求解器或对局记录取样，Texel 式拟合评估权重。
+func runTune(args []string) {
+	fs := flag.NewFlagSet("tune", flag.ExitOnError)
+	source := fs.String("source", "selfplay", "样本来源：selfplay | solver | records")
+	games := fs.Int("games", 200, "selfplay：自对弈局数")
+	depth := fs.Int("depth", 4, "selfplay：搜索深度")
+	plies := fs.Int("plies", 2, "selfplay：随机开局步数")
+	concurrency := fs.Int("concurrency", runtime.NumCPU(), "selfplay：同时进行的对局数")
+	records := fs.String("records", "", "records：对局记录文件的通配符，例如 \"tour/games/*.txt\"")
+	skip := fs.Int("skip", 0, "selfplay / records：跳过每局开头的步数")
+	positions := fs.Int("positions", 20000, "solver：每种旋转配置取样的局面数")
+	startPath := fs.String("start", "", "起始权重文件（默认为当前默认权重）")
+	outPath := fs.String("out", "weights.json", "输出的权重文件")
+	seed := fs.Int64("seed", 0, "随机种子（0 表示按当前时间）")
+	_ = fs.Parse(args)
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	start := game.DefaultWeights()
+	if *startPath != "" {
+		var err error
+		if start, err = game.LoadWeights(*startPath); err != nil {
+			fmt.Println("读取起始权重失败：", err)
+			return
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var samples []tune.Sample
+	switch *source {
+	case "selfplay":
+		fmt.Printf("自对弈 %d 局（深度 %d）...\n", *games, *depth)
+		recs, err := tune.SelfPlay(ctx, tune.SelfPlayConfig{
+			Games:        *games,
+			Depth:        *depth,
+			OpeningPlies: *plies,
+			Weights:      start,
+			Concurrency:  *concurrency,
+			Seed:         *seed,
+		})
+		if err != nil {
+			fmt.Println("自对弈中止：", err)
+			return
+		}
+		samples, _ = tune.FromRecords(recs, *skip)
+	case "records":
+		files, err := filepath.Glob(*records)
+		if err != nil || len(files) == 0 {
+			fmt.Println("没有找到对局记录：", *records)
+			return
+		}
+		var recs []*record.Record
+		for _, f := range files {
+			rec, err := record.Load(f)
+			if err != nil {
+				fmt.Println("读取对局记录失败：", err)
+				return
+			}
+			recs = append(recs, rec)
+		}
+		if samples, err = tune.FromRecords(recs, *skip); err != nil {
+			fmt.Println("对局记录无效：", err)
+			return
+		}
+	case "solver":
+		rng := rand.New(rand.NewSource(*seed))
+		// 互为镜像的配置共用一张表：只求解外圈顺时针的两种
+		for _, dirInner := range []game.Direction{game.Clockwise, game.CounterClockwise} {
+			fmt.Printf("正在求解：外圈%s，内圈%s ...\n", directionString(game.Clockwise), directionString(dirInner))
+			t := solver.Solve(game.Clockwise, dirInner)
+			samples = append(samples, tune.FromSolver(t, game.Clockwise, dirInner, *positions, rng)...)
+			samples = append(samples, tune.FromSolver(t, game.CounterClockwise, dirInner.Reverse(), *positions, rng)...)
+		}
+	default:
+		fmt.Println("source 参数无效，只能是 selfplay、solver 或 records。")
+		return
+	}
+	if len(samples) == 0 {
+		fmt.Println("没有可用的样本。")
+		return
+	}
+
+	fmt.Printf("共 %d 个样本，开始调参 ...\n", len(samples))
+	res := tune.Tune(samples, start, tune.Options{
+		Progress: func(step int, err float64, w game.Weights) {
+			fmt.Printf("  步长 %2d  误差 %.6f  %+v\n", step, err, w)
+		},
+	})
+	fmt.Printf("K = %.5f，误差 %.6f → %.6f（%d 遍）\n", res.K, res.Start, res.Error, res.Passes)
+	fmt.Printf("权重：%+v\n", res.Weights)
+	if err := res.Weights.Save(*outPath); err != nil {
+		fmt.Println("保存权重失败：", err)
+		return
+	}
+	fmt.Printf("权重已写入 %s；启动时加上 -weights %s 即可使用。\n", *outPath, *outPath)
+}
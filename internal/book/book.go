@@ -0,0 +1,191 @@
+// File book/book.go
+package book
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+
+	"trackLogicChess/internal/game"
+)
+
+// 开局库以规范局面（见 game.Canonical）为键：互为对称像的局面、以及两圈方向都相反的
+// 镜像配置下的局面共用同一条目。规范局面的外圈总是顺时针，所以库中只有 cw/cw 与 cw/ccw
+// 两种配置；记法中带有旋转方向，两种配置的条目互不混淆。条目中的着法按规范局面的坐标记录，
+// 查询时再变换回原局面。
+//
+// 文件为 JSON：
+//
+//	{
+//	  "source": "search depth=8 margin=10 eval=lines",
+//	  "positions": {
+//	    "4/4/4/4 b cw cw -": [{"row": 1, "col": 1, "weight": 100, "score": 12}, ...],
+//	    ...
+//	  }
+//	}
+
+// ErrBadBook 表示开局库文件内容无效。
+var ErrBadBook = errors.New("book: invalid opening book")
+
+// Entry 为库中局面的一个候选着法。
+type Entry struct {
+	Move   game.Move
+	Weight int // 选择权重，> 0
+	Score  int // 建库时的搜索分数（走棋方视角），求解器来源时为 0
+}
+
+// Book 为开局库。零值不可用，请用 New 或 Load 创建。
+type Book struct {
+	Source    string // 建库方式的说明
+	positions map[string][]Entry
+}
+
+type jsonEntry struct {
+	Row    int `json:"row"`
+	Col    int `json:"col"`
+	Weight int `json:"weight"`
+	Score  int `json:"score,omitempty"`
+}
+
+type jsonBook struct {
+	Source    string                 `json:"source,omitempty"`
+	Positions map[string][]jsonEntry `json:"positions"`
+}
+
+// New 返回一个空的开局库。
+func New(source string) *Book {
+	return &Book{Source: source, positions: make(map[string][]Entry)}
+}
+
+// Len 返回库中的局面数。
+func (b *Book) Len() int {
+	return len(b.positions)
+}
+
+// key 返回 g 的规范局面记法与从 g 到规范局面的对称
+func key(g *game.GameState) (string, game.Symmetry) {
+	c, sym := game.Canonical(g)
+	return game.FormatPosition(c), sym
+}
+
+// Contains 返回 g（按规范局面）是否已在库中。
+func (b *Book) Contains(g *game.GameState) bool {
+	k, _ := key(g)
+	_, ok := b.positions[k]
+	return ok
+}
+
+// Set 把局面 g 的候选着法设为 entries（着法为 g 中的坐标），覆盖已有条目。
+// 着法必须合法，权重必须为正。
+func (b *Book) Set(g *game.GameState, entries []Entry) error {
+	k, sym := key(g)
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Weight <= 0 {
+			return fmt.Errorf("%w: move %v has weight %d", ErrBadBook, e.Move, e.Weight)
+		}
+		if _, _, err := game.Play(g, e.Move); err != nil {
+			return fmt.Errorf("%w: move %v: %v", ErrBadBook, e.Move, err)
+		}
+		e.Move = sym.Move(e.Move)
+		out = append(out, e)
+	}
+	b.positions[k] = out
+	return nil
+}
+
+// Lookup 返回局面 g 的候选着法（已变换为 g 中的坐标），不在库中时返回 nil。
+func (b *Book) Lookup(g *game.GameState) []Entry {
+	k, sym := key(g)
+	entries := b.positions[k]
+	if len(entries) == 0 {
+		return nil
+	}
+	inv := sym.Inverse()
+	out := make([]Entry, len(entries))
+	for i, e := range entries {
+		e.Move = inv.Move(e.Move)
+		out[i] = e
+	}
+	return out
+}
+
+// Choose 按权重随机选出局面 g 的一个库内着法；不在库中时 ok 为 false。
+func (b *Book) Choose(g *game.GameState, rng *rand.Rand) (mv game.Move, ok bool) {
+	entries := b.Lookup(g)
+	total := 0
+	for _, e := range entries {
+		total += e.Weight
+	}
+	if total <= 0 {
+		return mv, false
+	}
+	r := rng.Intn(total)
+	for _, e := range entries {
+		if r < e.Weight {
+			return e.Move, true
+		}
+		r -= e.Weight
+	}
+	return mv, false
+}
+
+// Save 把开局库写为 JSON 文件。
+func (b *Book) Save(path string) error {
+	jb := jsonBook{Source: b.Source, Positions: make(map[string][]jsonEntry, len(b.positions))}
+	for k, entries := range b.positions {
+		js := make([]jsonEntry, len(entries))
+		for i, e := range entries {
+			js[i] = jsonEntry{Row: e.Move.Row, Col: e.Move.Col, Weight: e.Weight, Score: e.Score}
+		}
+		jb.Positions[k] = js
+	}
+	data, err := json.MarshalIndent(jb, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Load 读取开局库文件，并校验每个局面都是规范形式、着法合法、权重为正。
+func Load(path string) (*Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var jb jsonBook
+	if err := json.Unmarshal(data, &jb); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	b := New(jb.Source)
+	for k, js := range jb.Positions {
+		g, err := game.ParsePosition(k)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if c, _ := key(g); c != k {
+			return nil, fmt.Errorf("%w: %s: position %q is not canonical (want %q)", ErrBadBook, path, k, c)
+		}
+		entries := make([]Entry, len(js))
+		for i, e := range js {
+			entries[i] = Entry{Move: game.Move{Row: e.Row, Col: e.Col}, Weight: e.Weight, Score: e.Score}
+		}
+		if err := b.Set(g, entries); err != nil {
+			return nil, fmt.Errorf("%s: position %q: %w", path, k, err)
+		}
+	}
+	return b, nil
+}
+
+// Positions 返回库中全部规范局面的记法，按字典序排列。
+func (b *Book) Positions() []string {
+	out := make([]string, 0, len(b.positions))
+	for k := range b.positions {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
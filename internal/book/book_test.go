@@ -0,0 +1,154 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"trackLogicChess/internal/game"
+)
+
+// firstMoves 是测试用的来源：取前两个合法着法，权重 3 与 1
+type firstMoves struct{}
+
+func (firstMoves) Moves(ctx context.Context, g *game.GameState) ([]Entry, error) {
+	moves := g.GenerateMoves()
+	return []Entry{{Move: moves[0], Weight: 3}, {Move: moves[1], Weight: 1}}, nil
+}
+
+func openingPosition() *game.GameState {
+	g := game.NewGame(game.CounterClockwise, game.Clockwise)
+	_ = g.ApplyMove(0, 1)
+	_ = g.ApplyMove(2, 2)
+	return g
+}
+
+// TestLookupSymmetry 条目对局面的全部对称像（包括镜像配置）都可用，着法随之变换。
+func TestLookupSymmetry(t *testing.T) {
+	g := openingPosition()
+	b := New("test")
+	want := []Entry{{Move: game.Move{Row: 3, Col: 0}, Weight: 2}, {Move: game.Move{Row: 1, Col: 1}, Weight: 5}}
+	if err := b.Set(g, want); err != nil {
+		t.Fatal(err)
+	}
+	for s := game.Symmetry(0); s < game.NumSymmetries; s++ {
+		got := b.Lookup(s.Apply(g))
+		if len(got) != len(want) {
+			t.Fatalf("%v: %d entries", s, len(got))
+		}
+		for i := range want {
+			if got[i].Move != s.Move(want[i].Move) || got[i].Weight != want[i].Weight {
+				t.Fatalf("%v: entry %d is %+v, want move %v", s, i, got[i], s.Move(want[i].Move))
+			}
+		}
+	}
+	if b.Lookup(game.NewGame(game.Clockwise, game.Clockwise)) != nil {
+		t.Fatal("unknown position found in book")
+	}
+	var taken game.Move
+	for i := 0; i < 16; i++ {
+		if !g.Board.IsEmpty(i/4, i%4) {
+			taken = game.Move{Row: i / 4, Col: i % 4}
+		}
+	}
+	if err := b.Set(g, []Entry{{Move: taken, Weight: 1}}); !errors.Is(err, ErrBadBook) {
+		t.Fatalf("illegal move accepted: %v", err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	b, err := Build(context.Background(), firstMoves{}, BuildOptions{Plies: 3, Wide: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "book.json")
+	if err := b.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != b.Len() || got.Source != b.Source {
+		t.Fatalf("loaded %d positions (%q), saved %d (%q)", got.Len(), got.Source, b.Len(), b.Source)
+	}
+	for _, k := range b.Positions() {
+		g, _ := game.ParsePosition(k)
+		if x, y := b.Lookup(g), got.Lookup(g); len(x) != len(y) || x[0] != y[0] {
+			t.Fatalf("%s: %v != %v", k, x, y)
+		}
+	}
+
+	// 非规范局面的条目被拒绝
+	bad := `{"positions": {"4/4/4/4 b ccw cw -": [{"row": 1, "col": 1, "weight": 1}]}}`
+	if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrBadBook) {
+		t.Fatalf("non-canonical book loaded: %v", err)
+	}
+}
+
+// TestBuild 前 Wide 步展开全部着法，之后只沿库内着法展开；同一规范局面只收入一次。
+func TestBuild(t *testing.T) {
+	b, err := Build(context.Background(), firstMoves{}, BuildOptions{Plies: 3, Wide: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 第 0 层：两种规范配置的初始局面；第 1 层：每种配置 16 个着法按旋转归并为 4 个局面；
+	// 第 2 层：每个局面沿两个库内着法展开，至多 16 个
+	if n := b.Len(); n < 2+8 || n > 2+8+16 {
+		t.Fatalf("book has %d positions", n)
+	}
+	for _, k := range b.Positions() {
+		g, _ := game.ParsePosition(k)
+		if g.DirOuter != game.Clockwise {
+			t.Fatalf("position %q is not canonical", k)
+		}
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	b := New("test")
+	g := openingPosition()
+	if err := b.Set(g, []Entry{{Move: game.Move{Row: 3, Col: 0}, Weight: 3}, {Move: game.Move{Row: 1, Col: 1}, Weight: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	engine := &game.ScriptedPlayer{Moves: []game.Move{{Row: 3, Col: 3}}}
+	p := NewPlayer(b, engine, rand.New(rand.NewSource(1)))
+	hits := 0
+	p.OnHit = func(*game.GameState, game.Move) { hits++ }
+
+	counts := map[game.Move]int{}
+	for i := 0; i < 400; i++ {
+		mv, err := p.ChooseMove(context.Background(), g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[mv]++
+	}
+	if hits != 400 || counts[game.Move{Row: 3, Col: 0}] < 250 || counts[game.Move{Row: 1, Col: 1}] < 50 {
+		t.Fatalf("hits %d, counts %v", hits, counts)
+	}
+	// 不在库中时交给引擎
+	mv, err := p.ChooseMove(context.Background(), game.NewGame(game.Clockwise, game.Clockwise))
+	if err != nil || mv != (game.Move{Row: 3, Col: 3}) {
+		t.Fatalf("engine move %v, %v", mv, err)
+	}
+}
+
+func TestSearchSource(t *testing.T) {
+	src := SearchSource{Depth: 3, Margin: 10}
+	entries, err := src.Moves(context.Background(), openingPosition())
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("entries %v, %v", entries, err)
+	}
+	for _, e := range entries {
+		if e.Weight < 1 || e.Weight > 100 || entries[0].Score-e.Score > 10 {
+			t.Fatalf("bad entry %+v (best %+v)", e, entries[0])
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// File book/build.go
+package book
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+)
+
+/* ---------- 建库 ---------- */
+
+// Source 给出建库时一个局面的候选着法（g 中的坐标）。返回空切片表示该局面不收入库中。
+type Source interface {
+	Moves(ctx context.Context, g *game.GameState) ([]Entry, error)
+}
+
+// SearchSource 用 Analyze 逐个评估着法：收入分数与最佳着法相差不超过 Margin 的着法，
+// 权重按差距从 100 线性降到 1。已证明必胜时只收入取胜的着法，最快取胜者权重最高；
+// 必败的着法只在所有着法都必败时收入。
+type SearchSource struct {
+	Depth    int           // 搜索深度（0 表示搜到终局）
+	MoveTime time.Duration // 每个局面的分析时间上限（0 表示不限）
+	Margin   int           // 允许的分数差
+	Options  game.Options  // 创建搜索器的参数（评估、权重等）
+}
+
+// Moves 实现 Source。
+func (s SearchSource) Moves(ctx context.Context, g *game.GameState) ([]Entry, error) {
+	res := game.NewSearcherWith(s.Options).Analyze(ctx, g, game.Limits{Depth: s.Depth, MoveTime: s.MoveTime})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	best := res[0]
+	var out []Entry
+	for _, a := range res {
+		w := 0
+		switch {
+		case best.Mate > 0:
+			if a.Mate > 0 {
+				w = max(1, 100-10*(a.Mate-best.Mate))
+			}
+		case a.Mate < 0 && best.Mate == 0:
+		case best.Score-a.Score <= s.Margin:
+			w = 100
+			if s.Margin > 0 {
+				w = 1 + 99*(s.Margin-(best.Score-a.Score))/s.Margin
+			}
+		}
+		if w > 0 {
+			out = append(out, Entry{Move: a.Move, Weight: w, Score: a.Score})
+		}
+	}
+	return out, nil
+}
+
+// String 返回写入开局库 Source 字段的说明。
+func (s SearchSource) String() string {
+	str := fmt.Sprintf("search depth=%d", s.Depth)
+	if s.MoveTime > 0 {
+		str += fmt.Sprintf(" movetime=%v", s.MoveTime)
+	}
+	return str + fmt.Sprintf(" margin=%d eval=%v", s.Margin, s.Options.Eval)
+}
+
+// SolverSource 取求解表给出的全部最优着法，权重相同。Table 返回某一旋转配置的求解表
+// （规范局面的外圈总是顺时针，只会请求 cw/cw 与 cw/ccw 两种）。
+type SolverSource struct {
+	Table func(dirOuter, dirInner game.Direction) (*solver.Table, error)
+}
+
+// Moves 实现 Source。
+func (s SolverSource) Moves(ctx context.Context, g *game.GameState) ([]Entry, error) {
+	t, err := s.Table(g.DirOuter, g.DirInner)
+	if err != nil {
+		return nil, err
+	}
+	_, best := t.Probe(g)
+	out := make([]Entry, len(best))
+	for i, mv := range best {
+		out[i] = Entry{Move: mv, Weight: 1}
+	}
+	return out, ctx.Err()
+}
+
+// String 返回写入开局库 Source 字段的说明。
+func (s SolverSource) String() string {
+	return "solver"
+}
+
+// BuildOptions 为建库参数。
+type BuildOptions struct {
+	Plies int               // 收入距起点不足 Plies 步的局面
+	Wide  int               // 距起点不足 Wide 步的局面展开全部合法着法（覆盖对手的各种应着），其余只沿库内着法展开
+	Roots []*game.GameState // 起点；为空时为两种规范配置的初始局面
+
+	// Progress 每收入一个局面后调用，done 为已处理的局面数，queued 为待处理的局面数。
+	Progress func(done, queued int)
+}
+
+// Build 从起点出发按步数逐层建库：每个局面（按规范形式去重）向 src 取候选着法，
+// 再按 BuildOptions 展开下一层。ctx 取消时返回已建好的部分与 ctx.Err()。
+func Build(ctx context.Context, src Source, opts BuildOptions) (*Book, error) {
+	b := New(fmt.Sprint(src))
+	roots := opts.Roots
+	if len(roots) == 0 {
+		roots = []*game.GameState{
+			game.NewGame(game.Clockwise, game.Clockwise),
+			game.NewGame(game.Clockwise, game.CounterClockwise),
+		}
+	}
+	seen := make(map[string]bool)
+	var layer []*game.GameState
+	for _, g := range roots {
+		if k, _ := key(g); !g.IsGameOver() && !seen[k] {
+			seen[k] = true
+			layer = append(layer, g)
+		}
+	}
+	done := 0
+	for ply := 0; ply < opts.Plies && len(layer) > 0; ply++ {
+		var next []*game.GameState
+		for i, g := range layer {
+			entries, err := src.Moves(ctx, g)
+			if err != nil {
+				return b, err
+			}
+			if len(entries) > 0 {
+				if err := b.Set(g, entries); err != nil {
+					return b, err
+				}
+			}
+			done++
+			if opts.Progress != nil {
+				opts.Progress(done, len(layer)-i-1+len(next))
+			}
+			if ply+1 >= opts.Plies {
+				continue
+			}
+			moves := g.GenerateMoves()
+			if ply >= opts.Wide {
+				moves = moves[:0]
+				for _, e := range entries {
+					moves = append(moves, e.Move)
+				}
+			}
+			for _, mv := range moves {
+				child, res, _ := game.Play(g, mv)
+				if res.Over() {
+					continue
+				}
+				if k, _ := key(child); !seen[k] {
+					seen[k] = true
+					next = append(next, child)
+				}
+			}
+		}
+		layer = next
+	}
+	return b, nil
+}
@@ -0,0 +1,49 @@
+// File book/player.go
+package book
+
+import (
+	"context"
+	"io"
+	"math/rand"
+
+	"trackLogicChess/internal/game"
+)
+
+// Player 在引擎之前查询开局库：局面在库中时按权重随机选择库内着法，否则交给 Engine。
+// 同一个 Player 不能被多个 goroutine 同时使用。
+type Player struct {
+	Book   *Book
+	Engine game.Player
+
+	// OnHit 非 nil 时在选用库内着法后调用，例如用于打印提示。
+	OnHit func(g *game.GameState, mv game.Move)
+
+	rng *rand.Rand
+}
+
+// NewPlayer 返回由开局库 b 与引擎 engine 组成的 Player；rng 为 nil 时使用全局随机源。
+func NewPlayer(b *Book, engine game.Player, rng *rand.Rand) *Player {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return &Player{Book: b, Engine: engine, rng: rng}
+}
+
+// ChooseMove 实现 game.Player。
+func (p *Player) ChooseMove(ctx context.Context, g *game.GameState) (game.Move, error) {
+	if mv, ok := p.Book.Choose(g, p.rng); ok {
+		if p.OnHit != nil {
+			p.OnHit(g, mv)
+		}
+		return mv, nil
+	}
+	return p.Engine.ChooseMove(ctx, g)
+}
+
+// Close 关闭引擎（引擎实现 io.Closer 时）。
+func (p *Player) Close() error {
+	if c, ok := p.Engine.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
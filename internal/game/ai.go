@@ -2,7 +2,10 @@
 package game
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"math/bits"
 	"math/rand"
 	"time"
 	"trackLogicChess/internal/player"
@@ -16,136 +19,241 @@ type Move struct {
 	Col int
 }
 
-// opposite 返回相反颜色。
-func opposite(c player.Color) player.Color {
-	if c == player.Black {
-		return player.White
-	}
-	return player.Black
-}
-
-/* ---------- 启发式评估 ---------- */
-
-// lineScores[i]：一条 4 格直线里，己方有 i 子、对手 0 子 的加分（反之减分）。
-var lineScores = [5]int{0, 1, 8, 64, 1_000_000} // 4 连直接视为绝杀
-
-// heuristicScore 对整盘局面做线型统计。
-// - 10 条直线：4 行 + 4 列 + 2 对角。
-// - 若同一条线上双方都有子，计 0 分；否则按己/敌子数累加或累减。
-func heuristicScore(b *Board, me player.Color) int {
-	op := opposite(me)
-	score := 0
-
-	lines := [][4][2]int{
-		// 行
-		{{0, 0}, {0, 1}, {0, 2}, {0, 3}},
-		{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
-		{{2, 0}, {2, 1}, {2, 2}, {2, 3}},
-		{{3, 0}, {3, 1}, {3, 2}, {3, 3}},
-		// 列
-		{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
-		{{0, 1}, {1, 1}, {2, 1}, {3, 1}},
-		{{0, 2}, {1, 2}, {2, 2}, {3, 2}},
-		{{0, 3}, {1, 3}, {2, 3}, {3, 3}},
-		// 对角
-		{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
-		{{0, 3}, {1, 2}, {2, 1}, {3, 0}},
-	}
-
-	for _, ln := range lines {
-		myCnt, opCnt := 0, 0
-		for _, rc := range ln {
-			switch b.Get(rc[0], rc[1]) {
-			case me:
-				myCnt++
-			case op:
-				opCnt++
-			}
-		}
-		if myCnt > 0 && opCnt == 0 {
-			score += lineScores[myCnt]
-		} else if opCnt > 0 && myCnt == 0 {
-			score -= lineScores[opCnt]
-		}
-	}
-	return score
+// String 以 (row,col) 形式输出着法。
+func (m Move) String() string {
+	return fmt.Sprintf("(%d,%d)", m.Row, m.Col)
 }
 
 /* ---------- Negamax + α-β 剪枝 ---------- */
 
 const (
-	defaultDepth = 5         // 默认搜索深度
-	winScore     = 1_000_000 // 必胜分
-	loseScore    = -winScore
+	defaultDepth  = 5         // 默认搜索深度
+	defaultTTBits = 16        // FindBestMoveDeep 使用的置换表大小（2^16 条）
+	winScore      = 1_000_000 // 必胜分：距根结点 ply 步取胜记为 winScore - ply
+	loseScore     = -winScore
+	mateBound     = winScore - maxPly - 1 // |分数| ≥ mateBound 表示已证明的胜负
 )
 
-// FindBestMoveDeep 使用 Negamax 搜索给出最佳着法；depth ≤0 时采用 defaultDepth。
-func FindBestMoveDeep(g *GameState, depth int) Move {
-	if depth <= 0 {
-		depth = defaultDepth
+// terminalScore 返回终局（在距根结点 ply 步处结束）对 me 的评分：
+// 平局为 0；胜局越早分越高，负局越晚分越高。
+func terminalScore(winner, me player.Color, ply int) int {
+	switch winner {
+	case player.Empty:
+		return 0
+	case me:
+		return winScore - ply
 	}
+	return loseScore + ply
+}
+
+// SearchStats 记录搜索过程中的统计数据。
+type SearchStats struct {
+	Nodes     int64 // 访问的结点数
+	TTProbes  int64 // 置换表查询次数
+	TTHits    int64 // 查到同一局面的次数
+	TTCutoffs int64 // 直接用置换表结果返回的次数
+	Cutoffs   int64 // 内部结点发生 β 剪枝的次数
+	FirstCuts int64 // 其中由第一个着法引起的次数
+}
 
-	moves := g.GenerateMoves()
-	if len(moves) == 0 {
-		return Move{-1, -1}
+// HitRate 返回置换表命中率（0–1）。
+func (st SearchStats) HitRate() float64 {
+	if st.TTProbes == 0 {
+		return 0
 	}
+	return float64(st.TTHits) / float64(st.TTProbes)
+}
 
-	// 随机打乱，避免评分相同总走同一手
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+// FirstCutRate 返回 β 剪枝中由第一个着法引起的比例（0–1），衡量着法排序的好坏。
+func (st SearchStats) FirstCutRate() float64 {
+	if st.Cutoffs == 0 {
+		return 0
+	}
+	return float64(st.FirstCuts) / float64(st.Cutoffs)
+}
 
-	bestScore := math.MinInt
-	bestMove := moves[0]
+// Searcher 是可复用的 Negamax 搜索器，持有置换表、随机源与统计数据。
+// 同一个 Searcher 不能被多个 goroutine 同时使用。
+type Searcher struct {
+	tt      *TransTable
+	rng     *rand.Rand // 打乱根结点着法，使评分相同的着法轮流出现
+	eval    Eval       // 叶结点的静态评估（见 eval.go）
+	weights Weights    // EvalLines 的权重
+	stats   SearchStats
 
-	for _, mv := range moves {
-		sim := g.cloneGameState()
-		sim.Board.Set(mv.Row, mv.Col, sim.CurrentPlayer)
-		if CheckWin(sim.Board, sim.CurrentPlayer) { // 一步必杀
-			return mv
-		}
-		RotateOuter(sim.Board, sim.DirOuter)
-		RotateInner(sim.Board, sim.DirInner)
-		sim.CurrentPlayer = opposite(sim.CurrentPlayer)
+	ordering Ordering            // 内部结点的着法排序方式（见 ordering.go）
+	killers  [maxPly + 2][2]int8 // 每层最近两个引起 β 剪枝的着法
+	history  [3][16]int64        // 按走棋方与格子累计的剪枝得分，跨多次搜索衰减保留
 
-		score := -negamax(sim, depth-1, loseScore, winScore)
+	// 以下字段仅在一次 Search 期间有效（见 search.go）
+	stop  stopper
+	pv    [maxPly + 2][maxPly + 1]Move // 三角主变表：pv[ply] 为从 ply 开始的主变
+	pvLen [maxPly + 2]int
 
-		if score > bestScore {
-			bestScore, bestMove = score, mv
-		}
+	helpers []*Searcher // 多线程搜索的辅助搜索器（见 parallel.go）
+}
+
+// Options 为创建搜索器的参数。
+type Options struct {
+	TTBits  int        // 置换表含 2^TTBits 个条目，≤0 时为 defaultTTBits
+	Rand    *rand.Rand // 随机源；nil 时以当前时间为种子，每次运行结果不同
+	Eval    Eval       // 叶结点的静态评估，默认 EvalLines
+	Weights *Weights   // EvalLines 的权重；nil 时为创建时的 DefaultWeights()
+	Order   Ordering   // 内部结点的着法排序，默认 OrderFull
+}
+
+// NewSearcherWith 按 opts 创建搜索器。传入固定种子的 Rand 且单线程搜索时，
+// 同一局面序列上的着法完全可复现。
+func NewSearcherWith(opts Options) *Searcher {
+	if opts.TTBits <= 0 {
+		opts.TTBits = defaultTTBits
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	w := DefaultWeights()
+	if opts.Weights != nil {
+		w = *opts.Weights
+	}
+	return &Searcher{tt: NewTransTable(opts.TTBits), rng: opts.Rand, eval: opts.Eval, weights: w, ordering: opts.Order}
+}
+
+// NewSearcher 创建一个置换表含 2^ttBits 个条目、以当前时间为随机种子的搜索器。
+func NewSearcher(ttBits int) *Searcher {
+	return NewSearcherWith(Options{TTBits: ttBits})
+}
+
+// Stats 返回自创建或上次 ResetStats 以来的累计统计。
+func (s *Searcher) Stats() SearchStats {
+	return s.stats
+}
+
+// ResetStats 清零统计数据（置换表内容保留）。
+func (s *Searcher) ResetStats() {
+	s.stats = SearchStats{}
+}
+
+// FindBestMoveDeep 使用 Negamax 搜索给出最佳着法；depth ≤0 时采用 defaultDepth。
+func FindBestMoveDeep(g *GameState, depth int) Move {
+	return NewSearcher(defaultTTBits).BestMove(g, depth)
+}
+
+// BestMove 以固定深度搜索并返回最佳着法；depth ≤0 时采用 defaultDepth。
+func (s *Searcher) BestMove(g *GameState, depth int) Move {
+	if depth <= 0 {
+		depth = defaultDepth
 	}
-	return bestMove
+	return s.Search(context.Background(), g, Limits{Depth: depth}, nil).Move
 }
 
-// negamax 递归：当前 gs.CurrentPlayer 视角，返回局面评分。
-func negamax(gs *GameState, depth, alpha, beta int) int {
-	// 终局：上一手把对方连 4，则我方输
-	if CheckWin(gs.Board, opposite(gs.CurrentPlayer)) {
-		return loseScore + (defaultDepth - depth) // 越晚输分数越高（延迟被杀）
+// evaluate 按所选评估给出 gs 对走棋方的静态评分
+func (s *Searcher) evaluate(gs *GameState) int {
+	if s.eval == EvalRotation {
+		return rotationScore(gs)
 	}
-	// 深度到 0 或平局
-	if depth == 0 || gs.isBoardFull() {
-		return heuristicScore(gs.Board, gs.CurrentPlayer)
+	return s.weights.Evaluate(gs)
+}
+
+// negamax 递归：当前 gs.CurrentPlayer 视角，返回局面评分；ply 为距根结点的步数。
+// 先查置换表（以旋转对称下的规范局面为键，见 symmetry.go）：深度足够时直接利用其分数界剪枝，
+// 否则用记录的最佳着法参与排序（见 orderMoves）。
+// 搜索被中止时返回值无意义，调用方应检查 s.stop.stopped。
+func (s *Searcher) negamax(gs *GameState, depth, ply, alpha, beta int) int {
+	s.stats.Nodes++
+	s.pvLen[ply] = 0
+	if s.stop.check(s.stats.Nodes) {
+		return 0
+	}
+	// 终局已在走子时由 searchChild 判定，这里只需处理深度耗尽
+	if depth == 0 {
+		return s.evaluate(gs)
 	}
 
-	for _, mv := range gs.GenerateMoves() {
-		sim := gs.cloneGameState()
-		sim.Board.Set(mv.Row, mv.Col, sim.CurrentPlayer)
-		if CheckWin(sim.Board, sim.CurrentPlayer) {
-			return winScore - (defaultDepth - depth) // 越早杀分越高
+	ttMove := noMove
+	key, sym := canonicalKey(gs)
+	s.stats.TTProbes++
+	if e, ok := s.tt.probe(key); ok {
+		s.stats.TTHits++
+		if int(e.depth) >= depth {
+			score := scoreFromTT(int(e.score), ply)
+			switch {
+			case e.bound == BoundExact,
+				e.bound == BoundLower && score >= beta,
+				e.bound == BoundUpper && score <= alpha:
+				s.stats.TTCutoffs++
+				return score
+			}
 		}
-		RotateOuter(sim.Board, sim.DirOuter)
-		RotateInner(sim.Board, sim.DirInner)
-		sim.CurrentPlayer = opposite(sim.CurrentPlayer)
+		ttMove = sym.Inverse().square(e.move)
+	}
+
+	// 一步取胜时不必再搜。立即落败的着法总是最差的；让对手下一手取胜的着法在深度 ≥ 2 时
+	// 必然搜出 ply+2 步落败，也不会优于其他着法。两者在还有其他着法时直接略去（见 tactics.go）。
+	// OrderTT 保留加入着法排序之前的搜索以便对比，不做这些处理
+	var tac tacticSets
+	if s.ordering != OrderTT {
+		tac = tactics(gs)
+	}
+	if tac.wins != 0 {
+		mv := CellSet(tac.wins).Moves()[0]
+		score := winScore - (ply + 1)
+		s.pvLen[ply+1] = 0
+		s.updatePV(ply, mv)
+		s.tt.store(key, depth, scoreToTT(score, ply), BoundExact, sym.square(int8(mv.Row*4+mv.Col)))
+		return score
+	}
+	moves := gs.GenerateMoves()
+	if depth >= 2 {
+		moves = pruneMoves(moves, tac.losses|tac.hangs)
+	} else {
+		moves = pruneMoves(moves, tac.losses)
+	}
+	s.orderMoves(gs, moves, ttMove, tac.blocks, ply)
 
-		score := -negamax(sim, depth-1, -beta, -alpha)
+	alphaOrig := alpha
+	bestScore, bestMove := math.MinInt, noMove
+	for i, mv := range moves {
+		score := s.searchChild(gs, mv, depth, ply, alpha, beta)
+		if s.stop.stopped {
+			return 0
+		}
+		if score > bestScore {
+			bestScore, bestMove = score, int8(mv.Row*4+mv.Col)
+		}
 		if score > alpha {
 			alpha = score
+			s.updatePV(ply, mv)
 			if alpha >= beta { // β 剪枝
+				s.stats.Cutoffs++
+				if i == 0 {
+					s.stats.FirstCuts++
+				}
+				s.recordCutoff(gs, mv, depth, ply)
 				break
 			}
 		}
 	}
-	return alpha
+
+	bound := BoundExact
+	switch {
+	case bestScore <= alphaOrig:
+		bound = BoundUpper
+	case bestScore >= beta:
+		bound = BoundLower
+	}
+	s.tt.store(key, depth, scoreToTT(bestScore, ply), bound, sym.square(bestMove))
+	return bestScore
+}
+
+// searchChild 在 gs 上走 mv，返回 gs 走棋方视角的评分（窗口为 alpha..beta）。
+// 走子与终局判定都交给 Play（与 ApplyMove 相同的规则），未结束时继续递归。
+func (s *Searcher) searchChild(gs *GameState, mv Move, depth, ply, alpha, beta int) int {
+	sim, res, _ := Play(gs, mv)
+	if res.Over() {
+		s.pvLen[ply+1] = 0
+		return terminalScore(res.Winner(), gs.CurrentPlayer, ply+1)
+	}
+	return -s.negamax(sim, depth-1, ply+1, -beta, -alpha)
 }
 
 /* ---------- 原有辅助 ---------- */
@@ -155,34 +263,25 @@ func (g *GameState) GenerateMoves() []Move {
 	if g.GameOver {
 		return nil
 	}
-	var mv []Move
-	for r := 0; r < 4; r++ {
-		for c := 0; c < 4; c++ {
-			if g.Board.IsEmpty(r, c) {
-				mv = append(mv, Move{r, c})
-			}
-		}
+	empty := ^g.Board.occupied()
+	mv := make([]Move, 0, bits.OnesCount16(empty))
+	for empty != 0 {
+		i := bits.TrailingZeros16(empty)
+		mv = append(mv, Move{i / 4, i % 4})
+		empty &= empty - 1
 	}
 	return mv
 }
 
-// cloneBoard 深拷贝棋盘
-func (b *Board) cloneBoard() *Board {
-	var nb Board
-	for r := range b.cells {
-		copy(nb.cells[r][:], b.cells[r][:])
-	}
-	return &nb
-}
-
 // cloneGameState 深拷贝局面（含固定方向）
 func (g *GameState) cloneGameState() *GameState {
 	return &GameState{
-		Board:         g.Board.cloneBoard(),
+		Board:         g.Board.Clone(),
 		CurrentPlayer: g.CurrentPlayer,
 		DirOuter:      g.DirOuter,
 		DirInner:      g.DirInner,
 		Winner:        g.Winner,
 		GameOver:      g.GameOver,
+		key:           g.key,
 	}
 }
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"trackLogicChess/internal/player"
+)
+
+// benchPositions 返回基准测试使用的几个开局后局面。
+func benchPositions() []*GameState {
+	var out []*GameState
+	for _, dirs := range [][2]Direction{
+		{Clockwise, Clockwise},
+		{Clockwise, CounterClockwise},
+		{CounterClockwise, Clockwise},
+		{CounterClockwise, CounterClockwise},
+	} {
+		g := NewGame(dirs[0], dirs[1])
+		g.Board.Set(0, 0, player.Black)
+		g.Board.Set(1, 1, player.White)
+		g.Board.Set(2, 1, player.Black)
+		g.CurrentPlayer = player.White
+		g.Rehash()
+		out = append(out, g)
+	}
+	return out
+}
+
+// BenchmarkFindBestMoveDeep 统计搜索速度，nodes/s 即每秒访问的结点数，
+// nodes/op 为每次搜索的结点数，tt-hit% 为置换表命中率。
+func BenchmarkFindBestMoveDeep(b *testing.B) {
+	positions := benchPositions()
+	var st SearchStats
+	start := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := NewSearcher(defaultTTBits)
+		s.BestMove(positions[i%len(positions)], 5)
+		st.Nodes += s.Stats().Nodes
+		st.TTProbes += s.Stats().TTProbes
+		st.TTHits += s.Stats().TTHits
+	}
+	b.ReportMetric(float64(st.Nodes)/time.Since(start).Seconds(), "nodes/s")
+	b.ReportMetric(float64(st.Nodes)/float64(b.N), "nodes/op")
+	b.ReportMetric(100*st.HitRate(), "tt-hit%")
+}
+
+// BenchmarkParallelSearch 比较不同线程数下固定深度搜索的耗时，
+// 例如 go test -run=^$ -bench=ParallelSearch -cpu=8 ./internal/game。
+func BenchmarkParallelSearch(b *testing.B) {
+	positions := benchPositions()
+	for _, threads := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("threads=%d", threads), func(b *testing.B) {
+			var nodes int64
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				s := NewSearcher(defaultTTBits)
+				res := s.Search(context.Background(), positions[i%len(positions)], Limits{Depth: 7, Threads: threads}, nil)
+				nodes += res.Stats.Nodes
+			}
+			b.ReportMetric(float64(nodes)/time.Since(start).Seconds(), "nodes/s")
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// File game/analyze.go
+package game
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+/* ---------- 局面分析 ---------- */
+
+// MoveAnalysis 为分析中单个着法的结果，分数以走棋方为视角。
+type MoveAnalysis struct {
+	Move  Move
+	Score int
+	Mate  int    // >0：走此着后第 Mate 步（含本步）取胜；<0：第 -Mate 步落败；0：胜负未证明
+	PV    []Move // 以 Move 开头的主要变例
+}
+
+// MateIn 把搜索分数换算为距终局的步数：正数为取胜，负数为落败，0 表示未证明。
+func MateIn(score int) int {
+	switch {
+	case score >= mateBound:
+		return winScore - score
+	case score <= -mateBound:
+		return loseScore - score
+	}
+	return 0
+}
+
+// Analyze 用一个新的搜索器分析局面 g，见 Searcher.Analyze。
+func (g *GameState) Analyze(ctx context.Context, lim Limits) []MoveAnalysis {
+	return NewSearcher(defaultTTBits).Analyze(ctx, g, lim)
+}
+
+// Analyze 对 g 的每个合法着法都以全窗口做迭代加深搜索，给出精确分数与主变，
+// 按分数从高到低排列（同分按 GenerateMoves 的顺序）。
+// 预算用尽时返回最后一个完整完成的深度的结果；Limits.Threads 被忽略。
+// 所有着法的胜负都已证明时提前结束。
+func (s *Searcher) Analyze(ctx context.Context, g *GameState, lim Limits) []MoveAnalysis {
+	start := time.Now()
+	s.stop = stopper{ctx: ctx, nodeLimit: lim.Nodes}
+	s.resetOrdering()
+	if lim.Nodes > 0 {
+		s.stop.nodeLimit += s.stats.Nodes
+	}
+	if lim.MoveTime > 0 {
+		s.stop.deadline = start.Add(lim.MoveTime)
+	}
+
+	moves := g.GenerateMoves()
+	maxDepth := len(moves)
+	if lim.Depth > 0 && lim.Depth < maxDepth {
+		maxDepth = lim.Depth
+	}
+	var out []MoveAnalysis
+	for depth := 1; depth <= maxDepth; depth++ {
+		res, ok := s.analyzeDepth(g, moves, depth)
+		if !ok {
+			break
+		}
+		out = res
+		proven := true
+		for _, a := range out {
+			proven = proven && a.Mate != 0
+		}
+		if proven {
+			break
+		}
+	}
+	if out == nil && len(moves) > 0 {
+		// 第一层就被中止：仍给出全部着法，分数未知
+		for _, mv := range moves {
+			out = append(out, MoveAnalysis{Move: mv, PV: []Move{mv}})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// analyzeDepth 以深度 depth、全窗口逐个搜索 moves，返回各着法的精确分数与主变。
+// 搜索被中止时 ok 为 false。
+func (s *Searcher) analyzeDepth(g *GameState, moves []Move, depth int) (out []MoveAnalysis, ok bool) {
+	out = make([]MoveAnalysis, 0, len(moves))
+	for _, mv := range moves {
+		score := s.searchChild(g, mv, depth, 0, loseScore-1, winScore+1)
+		if s.stop.stopped {
+			return nil, false
+		}
+		s.updatePV(0, mv)
+		out = append(out, MoveAnalysis{
+			Move:  mv,
+			Score: score,
+			Mate:  MateIn(score),
+			PV:    s.extendPV(g, s.pv[0][:s.pvLen[0]], depth),
+		})
+	}
+	return out, true
+}
+
+// extendPV 复制主变 pv，并沿置换表记录的最佳着法把它补足到 maxLen 步或终局：
+// 置换表直接返回分数的结点不会写出后续主变。
+func (s *Searcher) extendPV(g *GameState, pv []Move, maxLen int) []Move {
+	out := append([]Move(nil), pv...)
+	cur := g
+	for _, mv := range out {
+		cur, _, _ = Play(cur, mv)
+	}
+	for len(out) < maxLen && !cur.IsGameOver() {
+		key, sym := canonicalKey(cur)
+		e, ok := s.tt.probe(key)
+		if !ok || e.move == noMove {
+			break
+		}
+		sq := sym.Inverse().square(e.move)
+		mv := Move{int(sq) / 4, int(sq) % 4}
+		next, _, err := Play(cur, mv)
+		if err != nil {
+			break
+		}
+		out, cur = append(out, mv), next
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// TestAnalyzeMatchesBruteForce 残局中每个着法的分数、胜负步数与主变都应与穷举一致。
+func TestAnalyzeMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	checked := 0
+	for checked < 100 {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		for len(g.GenerateMoves()) > 7 {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if g.IsGameOver() {
+			continue
+		}
+		checked++
+
+		res := g.Analyze(context.Background(), Limits{})
+		if len(res) != len(g.GenerateMoves()) {
+			t.Fatalf("analysed %d moves, want %d", len(res), len(g.GenerateMoves()))
+		}
+		for i, a := range res {
+			next, r, _ := Play(g, a.Move)
+			want := 0
+			switch {
+			case !r.Over():
+				br, bp := bruteForce(next)
+				want = expectedScore(-br, bp+1)
+			case r.Winner() == g.CurrentPlayer:
+				want = winScore - 1
+			case r.Winner() != 0:
+				want = loseScore + 1
+			}
+			if a.Score != want || a.Mate != MateIn(want) {
+				t.Fatalf("move %v: score %d mate %d, want %d\n%s", a.Move, a.Score, a.Mate, want, g.Board)
+			}
+			if i > 0 && a.Score > res[i-1].Score {
+				t.Fatalf("results not sorted: %d after %d", a.Score, res[i-1].Score)
+			}
+			if a.PV[0] != a.Move {
+				t.Fatalf("PV %v does not start with %v", a.PV, a.Move)
+			}
+			if a.Mate != 0 && len(a.PV) != abs(a.Mate) {
+				t.Fatalf("move %v: mate %d but PV %v", a.Move, a.Mate, a.PV)
+			}
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -7,23 +7,30 @@ import (
 	"trackLogicChess/internal/player"
 )
 
-// Board 表示一个 4×4 棋盘，cells[r][c] 存储第 r 行、第 c 列的棋子颜色。
-// 值为 player.Empty、player.Black 或 player.White。
+// Board 表示一个 4×4 棋盘，使用两张 16 位掩码（位棋盘）存储棋子：
+// 第 r 行、第 c 列对应第 r*4+c 位，black / white 分别记录黑子与白子。
+// 同一位不会同时出现在两张掩码中。
 type Board struct {
-	cells [4][4]player.Color
+	black uint16
+	white uint16
+}
+
+// fullMask 表示 16 格全部占满。
+const fullMask uint16 = 0xFFFF
+
+// bitAt 返回 (r, c) 对应的位掩码。
+// 越界时 panic，与此前二维数组越界的行为一致，避免静默读写到其他格子。
+func bitAt(r, c int) uint16 {
+	if uint(r) >= 4 || uint(c) >= 4 {
+		panic(fmt.Sprintf("game: board index (%d, %d) out of range", r, c))
+	}
+	return 1 << uint(r*4+c)
 }
 
 // NewBoard 返回一个全空（所有格子值为 player.Empty）的 4×4 棋盘。
 func NewBoard() *Board {
-	b := &Board{}
-	// Go 的数组会默认初始化为 0，本项目中 player.Empty == 0，
-	// 因此这里不需要显式赋值。若想确保，可如下写法：
-	// for r := 0; r < 4; r++ {
-	//     for c := 0; c < 4; c++ {
-	//         b.cells[r][c] = player.Empty
-	//     }
-	// }
-	return b
+	// 两张掩码的零值即为空棋盘，无需额外初始化。
+	return &Board{}
 }
 
 // IsEmpty 返回 (r, c) 位置是否为空（player.Empty）。
@@ -32,13 +39,22 @@ func (b *Board) IsEmpty(r, c int) bool {
 	if r < 0 || r >= 4 || c < 0 || c >= 4 {
 		return false
 	}
-	return b.cells[r][c] == player.Empty
+	return (b.black|b.white)&bitAt(r, c) == 0
 }
 
 // Set 在 (r, c) 位置放置一个颜色为 col 的棋子。
-// 不会做越界检查或重复落子检查，调用方需自行保证合法性。
+// 越界时 panic；不做重复落子检查，调用方需自行保证合法性。
+// col 为 player.Empty 时清空该格。
 func (b *Board) Set(r, c int, col player.Color) {
-	b.cells[r][c] = col
+	bit := bitAt(r, c)
+	b.black &^= bit
+	b.white &^= bit
+	switch col {
+	case player.Black:
+		b.black |= bit
+	case player.White:
+		b.white |= bit
+	}
 }
 
 // Get 返回 (r, c) 位置的棋子颜色。
@@ -47,7 +63,7 @@ func (b *Board) Get(r, c int) player.Color {
 	if r < 0 || r >= 4 || c < 0 || c >= 4 {
 		return player.Empty
 	}
-	return b.cells[r][c]
+	return b.Cell(r, c)
 }
 
 // String 将棋盘渲染为多行字符串，可用于终端打印调试。
@@ -59,7 +75,7 @@ func (b *Board) String() string {
 	var sb strings.Builder
 	for r := 0; r < 4; r++ {
 		for c := 0; c < 4; c++ {
-			switch b.cells[r][c] {
+			switch b.Cell(r, c) {
 			case player.Black:
 				sb.WriteString("○ ")
 			case player.White:
@@ -85,11 +101,39 @@ func (b *Board) DebugPrint() {
 }
 
 func (b *Board) Clone() *Board {
-	nb := *b // 值拷贝（数组元素也会被复制）
+	nb := *b // 值拷贝（两张掩码）
 	return &nb
 }
 
-// Cell 返回 (r,c) 处的棋子颜色
+// Cell 返回 (r,c) 处的棋子颜色，越界时 panic。
 func (b *Board) Cell(r, c int) player.Color {
-	return b.cells[r][c]
+	bit := bitAt(r, c)
+	switch {
+	case b.black&bit != 0:
+		return player.Black
+	case b.white&bit != 0:
+		return player.White
+	}
+	return player.Empty
+}
+
+// Masks 返回黑子与白子的位掩码（第 r*4+c 位对应 (r,c)），供求解器等工具做索引。
+func (b *Board) Masks() (black, white uint16) {
+	return b.black, b.white
+}
+
+// mask 返回颜色 col 的位掩码；player.Empty 返回空格掩码。
+func (b *Board) mask(col player.Color) uint16 {
+	switch col {
+	case player.Black:
+		return b.black
+	case player.White:
+		return b.white
+	}
+	return ^(b.black | b.white)
+}
+
+// occupied 返回所有已落子格子的掩码。
+func (b *Board) occupied() uint16 {
+	return b.black | b.white
 }
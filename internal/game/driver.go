@@ -0,0 +1,117 @@
+// File game/driver.go
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trackLogicChess/internal/player"
+)
+
+/* ---------- 对局驱动 ---------- */
+
+// Driver 让两个 Player 在一个 Session 上对弈，任意一方都可以是人类、引擎或脚本。
+type Driver struct {
+	Session *Session
+	Black   Player
+	White   Player
+
+	OnMove func(mv Move, g *GameState) // 每走一步（含重做）之后调用，可为 nil
+	OnUndo func(mv Move, g *GameState) // 每撤销一步之后调用，可为 nil
+}
+
+// NewDriver 创建从局面 g 开始、由 black 与 white 对弈的驱动器。
+func NewDriver(g *GameState, black, white Player) *Driver {
+	return &Driver{Session: NewSession(g), Black: black, White: white}
+}
+
+// PlayerFor 返回执 col 的 Player。
+func (d *Driver) PlayerFor(col player.Color) Player {
+	if col == player.White {
+		return d.White
+	}
+	return d.Black
+}
+
+// Run 轮流询问双方着法直到对局结束，返回结果。
+// Player 返回 ErrUndo / ErrRedo 时悔棋或重做后继续；返回其他错误或走出非法着法时中止并返回错误。
+func (d *Driver) Run(ctx context.Context) (Result, error) {
+	g := d.Session.State
+	for !g.IsGameOver() {
+		if err := ctx.Err(); err != nil {
+			return Ongoing, err
+		}
+		col := g.CurrentPlayer
+		mv, err := d.PlayerFor(col).ChooseMove(ctx, g.Clone())
+		switch {
+		case errors.Is(err, ErrUndo):
+			d.Undo()
+			continue
+		case errors.Is(err, ErrRedo):
+			d.Redo()
+			continue
+		case err != nil:
+			return Ongoing, fmt.Errorf("%v: %w", col, err)
+		}
+		if err := d.Session.Play(mv); err != nil {
+			return Ongoing, fmt.Errorf("%v played illegal move %v: %w", col, mv, err)
+		}
+		if d.OnMove != nil {
+			d.OnMove(mv, g)
+		}
+	}
+	return g.Result(), nil
+}
+
+// Undo 悔棋：至少撤销一步，并继续撤销直到轮到人类 Player（没有人类时只撤销一步）。
+// 没有可撤销的着法时返回 false。
+func (d *Driver) Undo() bool {
+	if !d.Session.CanUndo() {
+		return false
+	}
+	for d.Session.CanUndo() {
+		mv, _ := d.Session.Undo()
+		if d.OnUndo != nil {
+			d.OnUndo(mv, d.Session.State)
+		}
+		if d.humanToMove() || !d.hasHuman() {
+			break
+		}
+	}
+	return true
+}
+
+// Redo 重做：至少重做一步，并继续重做直到轮到人类 Player 或对局结束。
+// 没有可重做的着法时返回 false。
+func (d *Driver) Redo() bool {
+	if !d.Session.CanRedo() {
+		return false
+	}
+	for d.Session.CanRedo() {
+		mv, _ := d.Session.Redo()
+		if d.OnMove != nil {
+			d.OnMove(mv, d.Session.State)
+		}
+		if d.humanToMove() || !d.hasHuman() || d.Session.State.IsGameOver() {
+			break
+		}
+	}
+	return true
+}
+
+// humanToMove 返回当前走棋的一方是否为人类 Player。
+func (d *Driver) humanToMove() bool {
+	return isHuman(d.PlayerFor(d.Session.State.CurrentPlayer))
+}
+
+// hasHuman 返回双方中是否有人类 Player。
+func (d *Driver) hasHuman() bool {
+	return isHuman(d.Black) || isHuman(d.White)
+}
+
+// isHuman 判断 p 是否为人类 Player。
+func isHuman(p Player) bool {
+	h, ok := p.(HumanPlayer)
+	return ok && h.IsHuman()
+}
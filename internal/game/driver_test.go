@@ -0,0 +1,125 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"trackLogicChess/internal/player"
+)
+
+// randomGame 返回一局随机对局的着法序列与终局。
+func randomGame(rng *rand.Rand, dirOuter, dirInner Direction) ([]Move, *GameState) {
+	g := NewGame(dirOuter, dirInner)
+	var moves []Move
+	for !g.IsGameOver() {
+		legal := g.GenerateMoves()
+		mv := legal[rng.Intn(len(legal))]
+		_ = g.ApplyMove(mv.Row, mv.Col)
+		moves = append(moves, mv)
+	}
+	return moves, g
+}
+
+// split 把着法序列按黑白拆开。
+func split(moves []Move) (black, white []Move) {
+	for i, mv := range moves {
+		if i%2 == 0 {
+			black = append(black, mv)
+		} else {
+			white = append(white, mv)
+		}
+	}
+	return black, white
+}
+
+// TestDriverScripted 两个脚本 Player 应原样重现一局棋。
+func TestDriverScripted(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	for n := 0; n < 50; n++ {
+		moves, want := randomGame(rng, Direction(n%2), Direction(n/2%2))
+		black, white := split(moves)
+		d := NewDriver(NewGame(want.DirOuter, want.DirInner), &ScriptedPlayer{Moves: black}, &ScriptedPlayer{Moves: white})
+		var seen []Move
+		d.OnMove = func(mv Move, g *GameState) { seen = append(seen, mv) }
+		res, err := d.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != want.Result() || !sameState(d.Session.State, want) || len(seen) != len(moves) {
+			t.Fatalf("driver replay differs: %v vs %v", res, want.Result())
+		}
+	}
+}
+
+// TestDriverEngineEitherColor 引擎执任意一方都能与脚本 Player 下完一局。
+func TestDriverEngineEitherColor(t *testing.T) {
+	for _, engineColor := range []player.Color{player.Black, player.White} {
+		engine := NewEnginePlayer(Options{Rand: rand.New(rand.NewSource(1))}, Limits{Depth: 2})
+		random := PlayerFunc(func(ctx context.Context, g *GameState) (Move, error) {
+			return g.GenerateMoves()[0], nil
+		})
+		d := NewDriver(NewGame(Clockwise, CounterClockwise), engine, random)
+		if engineColor == player.White {
+			d.Black, d.White = random, engine
+		}
+		if _, err := d.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !d.Session.State.IsGameOver() {
+			t.Fatal("game did not finish")
+		}
+	}
+}
+
+// fakeHuman 先按 script 依次返回着法或错误。
+type fakeHuman struct {
+	script []any // Move 或 error
+}
+
+func (h *fakeHuman) IsHuman() bool { return true }
+
+func (h *fakeHuman) ChooseMove(ctx context.Context, g *GameState) (Move, error) {
+	if len(h.script) == 0 {
+		return Move{}, ErrScriptExhausted
+	}
+	step := h.script[0]
+	h.script = h.script[1:]
+	if err, ok := step.(error); ok {
+		return Move{}, err
+	}
+	return step.(Move), nil
+}
+
+// TestDriverUndoToHuman 人机对局中人类悔棋时，AI 的应着一并撤销；重做则一并恢复。
+func TestDriverUndoToHuman(t *testing.T) {
+	human := &fakeHuman{script: []any{Move{1, 1}, ErrUndo, ErrRedo, ErrUndo, Move{0, 0}}}
+	engine := &ScriptedPlayer{Moves: []Move{{3, 3}, {3, 0}}}
+	d := NewDriver(NewGame(Clockwise, Clockwise), human, engine)
+	var undone int
+	d.OnUndo = func(mv Move, g *GameState) { undone++ }
+	_, err := d.Run(context.Background())
+	if !errors.Is(err, ErrScriptExhausted) {
+		t.Fatalf("Run error = %v, want the exhausted script", err)
+	}
+	if undone != 4 {
+		t.Fatalf("undid %d moves, want 4", undone)
+	}
+	want := NewGame(Clockwise, Clockwise)
+	_ = want.ApplyMove(0, 0)
+	_ = want.ApplyMove(3, 0)
+	if !sameState(d.Session.State, want) {
+		t.Fatalf("final position\n%s\nwant\n%s", d.Session.State.Board, want.Board)
+	}
+}
+
+// TestDriverIllegalMove 非法着法使 Run 返回错误。
+func TestDriverIllegalMove(t *testing.T) {
+	d := NewDriver(NewGame(Clockwise, Clockwise),
+		&ScriptedPlayer{Moves: []Move{{1, 1}, {1, 2}}},
+		&ScriptedPlayer{Moves: []Move{{1, 2}}}) // (1,1) 旋转到 (1,2)
+	if _, err := d.Run(context.Background()); err == nil {
+		t.Fatal("illegal move accepted")
+	}
+}
@@ -0,0 +1,210 @@
+// File game/eval.go
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"os"
+)
+
+/* ---------- 可调参数的线性评估 ---------- */
+
+// 评估特征，均以走棋方视角计算（己方计数减对手计数）：
+//   - FeatLine1..3：直线上仅有己方 1–3 子（且无对手子）的条数；
+//   - FeatInner：内圈上的棋子数；
+//   - FeatRotated1..3：双环按规则旋转一次后，直线上仅有己方 1–3 子的条数，
+//     即下一手落子后这些棋子将被转到的位置。
+const (
+	FeatLine1 = iota
+	FeatLine2
+	FeatLine3
+	FeatInner
+	FeatRotated1
+	FeatRotated2
+	FeatRotated3
+	NumFeatures
+)
+
+// Features 为一个局面的特征向量。
+type Features [NumFeatures]int
+
+// Weights 为线性评估的权重：评分 = Σ 权重 × 特征。
+type Weights struct {
+	Lines   [3]int `json:"lines"`
+	Inner   int    `json:"inner"`
+	Rotated [3]int `json:"rotated"`
+}
+
+// innerMask 为内圈四格的位掩码
+const innerMask uint16 = 1<<5 | 1<<6 | 1<<9 | 1<<10
+
+// defaultWeights 为未指定权重的搜索器所用的权重，默认即原先手工选定的直线分值 1、8、64。
+var defaultWeights = Weights{Lines: [3]int{1, 8, 64}}
+
+// DefaultWeights 返回当前的默认权重。
+func DefaultWeights() Weights {
+	return defaultWeights
+}
+
+// SetDefaultWeights 设置此后新建的搜索器所用的默认权重，应在程序启动时、开始搜索前调用。
+func SetDefaultWeights(w Weights) {
+	defaultWeights = w
+}
+
+// Vector 以特征顺序返回权重。
+func (w Weights) Vector() Features {
+	return Features{w.Lines[0], w.Lines[1], w.Lines[2], w.Inner, w.Rotated[0], w.Rotated[1], w.Rotated[2]}
+}
+
+// WeightsFromVector 为 Vector 的逆操作。
+func WeightsFromVector(v Features) Weights {
+	return Weights{
+		Lines:   [3]int{v[FeatLine1], v[FeatLine2], v[FeatLine3]},
+		Inner:   v[FeatInner],
+		Rotated: [3]int{v[FeatRotated1], v[FeatRotated2], v[FeatRotated3]},
+	}
+}
+
+// Evaluate 返回局面 g 对走棋方的静态评分。
+func (w Weights) Evaluate(g *GameState) int {
+	f := features(g, w.Rotated != [3]int{})
+	v := w.Vector()
+	score := 0
+	for i := range f {
+		score += f[i] * v[i]
+	}
+	return score
+}
+
+// EvalFeatures 计算局面 g 的特征向量。
+func EvalFeatures(g *GameState) Features {
+	return features(g, true)
+}
+
+// features 计算特征向量；rotated 为 false 时省去旋转特征（记为 0）
+func features(g *GameState, rotated bool) Features {
+	var f Features
+	me := g.CurrentPlayer
+	my, op := g.Board.mask(me), g.Board.mask(me.Opponent())
+	lineCounts(my, op, f[FeatLine1:FeatLine3+1])
+	f[FeatInner] = bits.OnesCount16(my&innerMask) - bits.OnesCount16(op&innerMask)
+
+	if rotated {
+		t := &bothPerm[g.DirOuter][g.DirInner]
+		lineCounts(t.apply(my), t.apply(op), f[FeatRotated1:FeatRotated3+1])
+	}
+	return f
+}
+
+// lineCounts 把仅有一方 1–3 子的直线条数（己方加、对手减）累加到 out[0..2]
+func lineCounts(my, op uint16, out []int) {
+	for _, w := range winMasks {
+		myCnt := bits.OnesCount16(my & w)
+		opCnt := bits.OnesCount16(op & w)
+		switch {
+		case myCnt > 0 && opCnt == 0 && myCnt < 4:
+			out[myCnt-1]++
+		case opCnt > 0 && myCnt == 0 && opCnt < 4:
+			out[opCnt-1]--
+		}
+	}
+}
+
+// LoadWeights 从 JSON 文件读取权重。
+func LoadWeights(path string) (Weights, error) {
+	var w Weights
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return w, err
+	}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return w, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Save 把权重写为 JSON 文件。
+func (w Weights) Save(path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+/* ---------- 评估函数的选择 ---------- */
+
+// Eval 选择搜索叶结点使用的静态评估。
+type Eval int
+
+const (
+	EvalLines    Eval = iota // 按 Weights 的线性评估（默认）
+	EvalRotation             // 旋转感知评估，见 rotationScore
+)
+
+var evalNames = [...]string{"lines", "rotation"}
+
+// String 返回评估的名称（即 ParseEval 接受的写法）。
+func (e Eval) String() string {
+	if e < 0 || int(e) >= len(evalNames) {
+		return fmt.Sprintf("Eval(%d)", int(e))
+	}
+	return evalNames[e]
+}
+
+// ParseEval 解析评估名称：lines 或 rotation。
+func ParseEval(s string) (Eval, error) {
+	for i, name := range evalNames {
+		if s == name {
+			return Eval(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown evaluation %q (want lines or rotation)", s)
+}
+
+/* ---------- 旋转感知评估 ---------- */
+
+// 走棋方落子后双环立即旋转，对手落子后再旋转一次，所以静态地数棋盘上的直线并不可靠：
+// 走棋方能利用的是旋转一次后的排列（落子点可以是旋转后的任一空格），
+// 对手能利用的则是旋转两次后的排列。rotationScore 在各自的排列上数直线，
+// 必然的胜负则取自 tactics（见 tactics.go）：
+//   - 有一步取胜的着法：必胜；
+//   - 每个着法都立即落败，或都让对手下一手取胜：必负；
+//   - 不输的着法都立即和棋（双方同时成线或下满棋盘）：0 分。
+const (
+	threatScore = 10_000 // 必然胜负的分值，远小于 mateBound，不会与已证明的胜负混淆
+)
+
+// rotLineScores[i]：在对应排列中，一条直线上仅有一方 i 子时的分值。
+var rotLineScores = [4]int{0, 1, 8, 64}
+
+// rotationScore 返回局面 g（未结束）对走棋方的旋转感知评分。
+func rotationScore(g *GameState) int {
+	empty := ^g.Board.occupied()
+	switch tac := tactics(g); {
+	case tac.wins != 0:
+		return threatScore
+	case tac.losses == empty, tac.hangs == empty:
+		return -threatScore
+	case tac.losses|tac.draws == empty:
+		return 0 // 不输的着法都立即和棋
+	}
+
+	t := &bothPerm[g.DirOuter][g.DirInner]
+	me := g.CurrentPlayer
+	my1, op1 := t.apply(g.Board.mask(me)), t.apply(g.Board.mask(me.Opponent()))
+	my2, op2 := t.apply(my1), t.apply(op1)
+	score := 0
+	for _, w := range winMasks {
+		// 己方：旋转一次后的排列
+		if n := bits.OnesCount16(my1 & w); op1&w == 0 && n > 0 {
+			score += rotLineScores[min(n, 3)]
+		}
+		// 对手：旋转两次后的排列
+		if n := bits.OnesCount16(op2 & w); my2&w == 0 && n > 0 {
+			score -= rotLineScores[min(n, 3)]
+		}
+	}
+	return score
+}
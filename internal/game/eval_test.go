@@ -0,0 +1,143 @@
+package game
+
+import (
+	"math/bits"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"trackLogicChess/internal/player"
+)
+
+// oldHeuristic 为调参前手工选定的直线评估（lineScores = {0,1,8,64,…}）
+func oldHeuristic(b *Board, me player.Color) int {
+	lineScores := [5]int{0, 1, 8, 64, 1_000_000}
+	my, op := b.mask(me), b.mask(me.Opponent())
+	score := 0
+	for _, w := range winMasks {
+		myCnt, opCnt := bits.OnesCount16(my&w), bits.OnesCount16(op&w)
+		if myCnt > 0 && opCnt == 0 {
+			score += lineScores[myCnt]
+		} else if opCnt > 0 && myCnt == 0 {
+			score -= lineScores[opCnt]
+		}
+	}
+	return score
+}
+
+func TestDefaultWeightsMatchLineHeuristic(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	w := DefaultWeights()
+	for n := 0; n < 200; n++ {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		for !g.IsGameOver() {
+			if got, want := w.Evaluate(g), oldHeuristic(g.Board, g.CurrentPlayer); got != want {
+				t.Fatalf("%s: evaluate %d, want %d", FormatPosition(g), got, want)
+			}
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+}
+
+func TestEvalFeatures(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for n := 0; n < 100; n++ {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		for !g.IsGameOver() {
+			f := EvalFeatures(g)
+
+			// 旋转特征即旋转后棋盘上的直线特征
+			r := g.Clone()
+			Rotate(r.Board, r.DirOuter, r.DirInner)
+			fr := EvalFeatures(r)
+			for i := 0; i < 3; i++ {
+				if f[FeatRotated1+i] != fr[FeatLine1+i] {
+					t.Fatalf("%s: rotated feature %d = %d, want %d", FormatPosition(g), i+1, f[FeatRotated1+i], fr[FeatLine1+i])
+				}
+			}
+			// 换走棋方时特征取反
+			o := g.Clone()
+			o.CurrentPlayer = o.CurrentPlayer.Opponent()
+			fo := EvalFeatures(o)
+			for i := range f {
+				if fo[i] != -f[i] {
+					t.Fatalf("%s: feature %d = %d for the mover, %d for the opponent", FormatPosition(g), i, f[i], fo[i])
+				}
+			}
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+
+	g := NewGame(Clockwise, Clockwise)
+	g.Board = boardFrom("B....BB...W.....") // 黑子两枚在内圈、一枚在外圈，白子一枚在内圈
+	if f := EvalFeatures(g); f[FeatInner] != 1 {
+		t.Errorf("inner = %d, want 1", f[FeatInner])
+	}
+}
+
+func TestWeightsSaveLoad(t *testing.T) {
+	w := Weights{Lines: [3]int{2, 9, 70}, Inner: 3, Rotated: [3]int{1, 4, 30}}
+	if WeightsFromVector(w.Vector()) != w {
+		t.Fatalf("vector round trip changed weights")
+	}
+	path := filepath.Join(t.TempDir(), "w.json")
+	if err := w.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadWeights(path)
+	if err != nil || got != w {
+		t.Fatalf("loaded %+v, %v", got, err)
+	}
+}
+
+// TestRotationScoreThreats 用穷举核对旋转感知评估识别的必然胜负：
+// 评为必胜当且仅当存在一步取胜的着法；评为必负的局面确实必负。
+func TestRotationScoreThreats(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	wins, losses := 0, 0
+	for n := 0; n < 300; n++ {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		for !g.IsGameOver() {
+			score := rotationScore(g)
+			winNow := false
+			for _, mv := range g.GenerateMoves() {
+				if _, res, _ := Play(g, mv); res.Winner() == g.CurrentPlayer {
+					winNow = true
+				}
+			}
+			if (score == threatScore) != winNow {
+				t.Fatalf("%s: score %d, win in one %v", FormatPosition(g), score, winNow)
+			}
+			if score == -threatScore && bits.OnesCount16(g.Board.occupied()) >= 6 {
+				if r, _ := bruteForce(g); r != -1 {
+					t.Fatalf("%s: scored as lost but brute force gives %d", FormatPosition(g), r)
+				}
+				losses++
+			}
+			if winNow {
+				wins++
+			}
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+	if wins == 0 || losses == 0 {
+		t.Errorf("too few threats seen: %d wins, %d losses", wins, losses)
+	}
+}
+
+func TestParseEval(t *testing.T) {
+	for _, e := range []Eval{EvalLines, EvalRotation} {
+		if got, err := ParseEval(e.String()); err != nil || got != e {
+			t.Errorf("ParseEval(%q) = %v, %v", e.String(), got, err)
+		}
+	}
+	if _, err := ParseEval("material"); err == nil {
+		t.Error("ParseEval accepted an unknown name")
+	}
+}
@@ -16,6 +16,33 @@ const (
 	CounterClockwise
 )
 
+// String 返回方向在局面记法中的写法："cw" 或 "ccw"。
+func (d Direction) String() string {
+	if d == CounterClockwise {
+		return "ccw"
+	}
+	return "cw"
+}
+
+// Reverse 返回相反的旋转方向。
+func (d Direction) Reverse() Direction {
+	if d == Clockwise {
+		return CounterClockwise
+	}
+	return Clockwise
+}
+
+// ParseDirection 解析 "cw" 或 "ccw"。
+func ParseDirection(s string) (Direction, error) {
+	switch s {
+	case "cw":
+		return Clockwise, nil
+	case "ccw":
+		return CounterClockwise, nil
+	}
+	return 0, fmt.Errorf("invalid direction %q, want cw or ccw", s)
+}
+
 // GameState 保存当前游戏的状态，包括棋盘、当前玩家、固定的旋转方向、胜者和是否结束。
 type GameState struct {
 	Board         *Board       // 4×4 棋盘
@@ -24,6 +51,8 @@ type GameState struct {
 	DirInner      Direction    // 启动时固定的“内圈”旋转方向
 	Winner        player.Color // 胜者 (Black、White，或 Empty 表示平局/无胜者)
 	GameOver      bool         // 游戏是否结束
+
+	key uint64 // Zobrist 键，随落子、旋转与换手增量更新（见 zobrist.go）
 }
 
 // NewGame 新建一个 GameState，需要传入固定的外圈和内圈方向。
@@ -31,7 +60,7 @@ type GameState struct {
 //
 //	g := NewGame(Clockwise, CounterClockwise)
 func NewGame(dirOuter, dirInner Direction) *GameState {
-	return &GameState{
+	g := &GameState{
 		Board:         NewBoard(),
 		CurrentPlayer: player.Black,
 		DirOuter:      dirOuter,
@@ -39,90 +68,155 @@ func NewGame(dirOuter, dirInner Direction) *GameState {
 		Winner:        player.Empty,
 		GameOver:      false,
 	}
+	g.Rehash()
+	return g
 }
 
 // ApplyMove 在 (r,c) 位置落子，然后对外圈和内圈执行“固定方向”旋转。
 // 旋转方向由 g.DirOuter 和 g.DirInner 决定，后续不允许修改。
-// 落子完成并旋转后，再判断当前玩家是否连成 4 子。
+// 落子完成并旋转后，按 Outcome 判定胜负。
 // 参数 r,c 均在 0–3 范围内；如果出错（格子已占用或游戏已结束），返回非 nil 错误。
 func (g *GameState) ApplyMove(r, c int) error {
-	// 1. 检查游戏状态与目标格合法性
+	if err := g.checkMove(Move{r, c}); err != nil {
+		return err
+	}
+	g.play(Move{r, c})
+	return nil
+}
+
+// Play 返回在 g 上走 mv 之后的新局面及其结果，g 本身不会被修改。
+// 着法不合法（格子已占用、越界或游戏已结束）时返回错误。
+func Play(g *GameState, mv Move) (*GameState, Result, error) {
+	if err := g.checkMove(mv); err != nil {
+		return nil, Ongoing, err
+	}
+	next := g.cloneGameState()
+	return next, next.play(mv), nil
+}
+
+// checkMove 检查 mv 在当前局面是否合法。
+func (g *GameState) checkMove(mv Move) error {
 	if g.GameOver {
 		return errors.New("game already over")
 	}
-	if !g.Board.IsEmpty(r, c) {
+	if !g.Board.IsEmpty(mv.Row, mv.Col) {
 		return errors.New("cell not empty")
 	}
+	return nil
+}
 
-	// 2. 在 (r,c) 放置当前玩家的棋子
-	g.Board.Set(r, c, g.CurrentPlayer)
+// play 就地走一步：落子、旋转两圈、判定结果；对局未结束时换手。
+// 调用方需保证着法合法。
+func (g *GameState) play(mv Move) Result {
+	g.place(mv.Row, mv.Col)
+	g.rotate()
+	res := Outcome(g.Board, g.CurrentPlayer)
+	if res.Over() {
+		g.Winner = res.Winner()
+		g.GameOver = true
+		return res
+	}
+	g.switchPlayer()
+	return res
+}
 
-	// 打印落子后但未旋转前的棋盘（可选调试）
-	//fmt.Println("\n--- 旋转前棋盘 ---")
-	//g.Board.DebugPrint()
+// undo 撤销最后一步 mv（必须是最近一次 play 的着法）：反向旋转两圈、移走棋子，
+// 恢复走棋方并清除胜负状态。
+func (g *GameState) undo(mv Move) {
+	if !g.GameOver {
+		g.switchPlayer() // 对局结束时 play 不会换手
+	}
+	g.unrotate()
+	g.unplace(mv.Row, mv.Col)
+	g.Winner = player.Empty
+	g.GameOver = false
+}
 
-	// 3. 使用固定方向做旋转
-	RotateOuter(g.Board, g.DirOuter)
-	RotateInner(g.Board, g.DirInner)
+// Clone 返回局面的深拷贝，修改副本不会影响原局面。
+func (g *GameState) Clone() *GameState {
+	return g.cloneGameState()
+}
 
-	//打印旋转后的棋盘（可选调试）
-	fmt.Println("\n--- 旋转后棋盘 ---")
-	g.Board.DebugPrint()
+// Result 表示对局的结果。
+type Result int
 
-	// 4. 旋转完成后，先判断当前玩家和对手是否同时连成 4
-	selfWin := CheckWin(g.Board, g.CurrentPlayer)
-	opp := player.Empty
-	if g.CurrentPlayer == player.Black {
-		opp = player.White
-	} else {
-		opp = player.Black
-	}
-	oppWin := CheckWin(g.Board, opp)
+const (
+	Ongoing   Result = iota // 对局未结束
+	BlackWins               // 黑方胜
+	WhiteWins               // 白方胜
+	Draw                    // 平局
+)
 
-	if selfWin && oppWin {
-		// 双方同时连 4，判平局
-		g.Winner = player.Empty
-		g.GameOver = true
-		return nil
-	}
-	if selfWin {
-		// 只有自己连接 4
-		g.Winner = g.CurrentPlayer
-		g.GameOver = true
-		return nil
+// String 返回 Result 的可读字符串。
+func (r Result) String() string {
+	switch r {
+	case BlackWins:
+		return "BlackWins"
+	case WhiteWins:
+		return "WhiteWins"
+	case Draw:
+		return "Draw"
+	default:
+		return "Ongoing"
 	}
-	if oppWin {
-		// 只有对手连接 4（因为旋转导致自杀）
-		g.Winner = opp
-		g.GameOver = true
-		return nil
+}
+
+// Over 返回对局是否已结束。
+func (r Result) Over() bool {
+	return r != Ongoing
+}
+
+// Winner 返回胜者颜色；平局或未结束时返回 player.Empty。
+func (r Result) Winner() player.Color {
+	switch r {
+	case BlackWins:
+		return player.Black
+	case WhiteWins:
+		return player.White
 	}
+	return player.Empty
+}
 
-	// 5. 如果棋盘已满且无人连成 4，则平局
-	if g.isBoardFull() {
-		g.GameOver = true
-		return nil
+// winResult 返回 col 获胜对应的 Result。
+func winResult(col player.Color) Result {
+	if col == player.Black {
+		return BlackWins
 	}
+	return WhiteWins
+}
 
-	// 6. 切换到下一玩家
-	if g.CurrentPlayer == player.Black {
-		g.CurrentPlayer = player.White
-	} else {
-		g.CurrentPlayer = player.Black
+// Outcome 在落子并旋转之后判定对局结果，mover 为刚落子的一方：
+//   - 双方同时连成 4 子，判平局；
+//   - 只有一方连成 4 子，该方获胜（旋转可能让对手连成 4 子）；
+//   - 无人连成 4 子但棋盘已满，判平局；
+//   - 否则对局继续，返回 Ongoing。
+//
+// ApplyMove、Play、AI 搜索与求解器都以此为唯一的终局规则。
+func Outcome(b *Board, mover player.Color) Result {
+	selfWin := CheckWin(b, mover)
+	oppWin := CheckWin(b, mover.Opponent())
+	switch {
+	case selfWin && oppWin:
+		return Draw
+	case selfWin:
+		return winResult(mover)
+	case oppWin:
+		return winResult(mover.Opponent())
+	case b.occupied() == fullMask:
+		return Draw
 	}
-	return nil
+	return Ongoing
 }
 
-// isBoardFull 判断棋盘是否已满
-func (g *GameState) isBoardFull() bool {
-	for r := 0; r < 4; r++ {
-		for c := 0; c < 4; c++ {
-			if g.Board.IsEmpty(r, c) {
-				return false
-			}
-		}
+// Result 返回局面当前的对局结果。
+func (g *GameState) Result() Result {
+	switch {
+	case !g.GameOver:
+		return Ongoing
+	case g.Winner == player.Empty:
+		return Draw
 	}
-	return true
+	return winResult(g.Winner)
 }
 
 // IsGameOver 返回游戏是否结束。
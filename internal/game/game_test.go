@@ -0,0 +1,75 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"trackLogicChess/internal/player"
+)
+
+// boardFrom 按行优先的 16 个字符构造棋盘：'B' 黑、'W' 白、'.' 空。
+func boardFrom(s string) *Board {
+	b := NewBoard()
+	for i, ch := range s {
+		switch ch {
+		case 'B':
+			b.Set(i/4, i%4, player.Black)
+		case 'W':
+			b.Set(i/4, i%4, player.White)
+		}
+	}
+	return b
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		mover player.Color
+		want  Result
+	}{
+		{"ongoing", "B...W...........", player.Black, Ongoing},
+		{"self win", "BBBBWW.W........", player.Black, BlackWins},
+		{"rotation hands opponent the line", "BBBBWW.W........", player.White, BlackWins},
+		{"both lines", "BBBBWWWW........", player.White, Draw},
+		{"full board", "BWBWBWBWWBWBWBWB", player.Black, Draw},
+	}
+	for _, tt := range tests {
+		if got := Outcome(boardFrom(tt.board), tt.mover); got != tt.want {
+			t.Errorf("%s: Outcome = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPlayMatchesApplyMove Play 不修改输入，且与 ApplyMove 得到相同的局面与结果。
+func TestPlayMatchesApplyMove(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for n := 0; n < 200; n++ {
+		g := NewGame(Direction(n%2), Direction(n/2%2))
+		for !g.IsGameOver() {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+
+			before := g.Clone()
+			next, res, err := Play(g, mv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *g.Board != *before.Board || g.CurrentPlayer != before.CurrentPlayer || g.Key() != before.Key() {
+				t.Fatalf("Play modified its input\n%s", before.Board)
+			}
+			if err := g.ApplyMove(mv.Row, mv.Col); err != nil {
+				t.Fatal(err)
+			}
+			if *next.Board != *g.Board || next.CurrentPlayer != g.CurrentPlayer || next.Key() != g.Key() {
+				t.Fatalf("Play and ApplyMove disagree on %v\n%s", mv, before.Board)
+			}
+			if res != g.Result() {
+				t.Fatalf("Play result %v, ApplyMove result %v", res, g.Result())
+			}
+		}
+		if _, _, err := Play(g, Move{0, 0}); err == nil {
+			t.Fatal("Play accepted a move after the game ended")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// File game/level.go
+package game
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+/* ---------- 难度等级 ---------- */
+
+// Level 为 AI 的难度等级，从 Beginner 到 Perfect 依次增强。
+type Level int
+
+const (
+	Beginner Level = iota
+	Easy
+	Medium
+	Hard
+	Expert
+	Perfect
+)
+
+var levelNames = [...]string{"beginner", "easy", "medium", "hard", "expert", "perfect"}
+
+// String 返回等级的名称（即 ParseLevel 接受的写法）。
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel 解析等级名称（不区分大小写）。
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level %q (want one of %s)", s, strings.Join(levelNames[:], ", "))
+}
+
+// levelParams 描述一个等级如何选着：
+// 先以 depth 层搜索给每个候选着法打分，再按 exp(分差/temperature) 的权重随机抽取；
+// 另有 blunder 的概率不看分数、随机走一步非最佳着法。
+// temperature 与 blunder 都为 0 时直接采用 Search 的结果；depth 为 0 表示搜到终局。
+type levelParams struct {
+	depth       int
+	temperature float64
+	blunder     float64
+}
+
+var levelTable = [...]levelParams{
+	Beginner: {depth: 1, temperature: 48, blunder: 0.25},
+	Easy:     {depth: 2, temperature: 24, blunder: 0.10},
+	Medium:   {depth: 3, temperature: 8, blunder: 0.03},
+	Hard:     {depth: 5, temperature: 2},
+	Expert:   {depth: 8},
+	Perfect:  {depth: 0},
+}
+
+// FindMoveAtLevel 以难度 level 为 g 选择一步着法。
+func FindMoveAtLevel(g *GameState, level Level) Move {
+	return NewSearcher(defaultTTBits).LevelMove(g, level)
+}
+
+// LevelMove 以难度 level 为 g 选择一步着法；无合法着法时返回 (-1,-1)。
+// 随机性来自搜索器的随机源，固定种子时结果可复现。
+func (s *Searcher) LevelMove(g *GameState, level Level) Move {
+	mv, _ := s.levelMove(context.Background(), g, level)
+	return mv
+}
+
+// levelMove 实现 LevelMove；ctx 取消时尽快返回，ok 为 false。
+func (s *Searcher) levelMove(ctx context.Context, g *GameState, level Level) (mv Move, ok bool) {
+	p := levelTable[Perfect]
+	if level >= 0 && int(level) < len(levelTable) {
+		p = levelTable[level]
+	}
+	if p.temperature == 0 && p.blunder == 0 {
+		mv = s.Search(ctx, g, Limits{Depth: p.depth}, nil).Move
+		return mv, !s.stop.stopped
+	}
+
+	moves := g.GenerateMoves()
+	if len(moves) == 0 {
+		return Move{-1, -1}, true
+	}
+	s.rng.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+	s.stop = stopper{ctx: ctx}
+	depth := p.depth
+	if depth <= 0 || depth > len(moves) {
+		depth = len(moves)
+	}
+	res, ok := s.analyzeDepth(g, moves, depth)
+	if !ok {
+		return Move{-1, -1}, false
+	}
+	scores := make([]int, len(res))
+	for i, a := range res {
+		scores[i] = a.Score
+	}
+	best := 0
+	for i, sc := range scores {
+		if sc > scores[best] {
+			best = i
+		}
+	}
+	if len(moves) > 1 && s.rng.Float64() < p.blunder {
+		i := s.rng.Intn(len(moves) - 1)
+		if i >= best {
+			i++
+		}
+		return moves[i], true
+	}
+	return moves[softmaxPick(scores, scores[best], p.temperature, s.rng.Float64())], true
+}
+
+// softmaxPick 按权重 exp((score-top)/temperature) 抽取一个下标，u 为 [0,1) 上的随机数。
+func softmaxPick(scores []int, top int, temperature, u float64) int {
+	weights := make([]float64, len(scores))
+	sum := 0.0
+	for i, sc := range scores {
+		weights[i] = math.Exp(float64(sc-top) / temperature)
+		sum += weights[i]
+	}
+	u *= sum
+	for i, w := range weights {
+		if u < w {
+			return i
+		}
+		u -= w
+	}
+	return len(scores) - 1
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for l := Beginner; l <= Perfect; l++ {
+		got, err := ParseLevel(l.String())
+		if err != nil || got != l {
+			t.Errorf("ParseLevel(%q) = %v, %v", l.String(), got, err)
+		}
+	}
+	if got, err := ParseLevel("Hard"); err != nil || got != Hard {
+		t.Errorf("ParseLevel(\"Hard\") = %v, %v", got, err)
+	}
+	if _, err := ParseLevel("grandmaster"); err == nil {
+		t.Error("ParseLevel accepted an unknown level")
+	}
+}
+
+func TestSoftmaxPick(t *testing.T) {
+	scores := []int{0, 10, -1_000_000}
+	if i := softmaxPick(scores, 10, 0.01, 0.999); i != 1 {
+		t.Errorf("cold softmax picked %d, want the best move", i)
+	}
+	if i := softmaxPick(scores, 10, 1e9, 0.2); i != 0 {
+		t.Errorf("hot softmax with u=0.2 picked %d, want 0", i)
+	}
+}
+
+// TestLevelsPlayLegalMoves 各等级都给出合法着法；同一种子下结果可复现；
+// 较强的等级在能一步取胜时不会放走胜局（可能选择稍慢的胜法）。
+func TestLevelsPlayLegalMoves(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	for n := 0; n < 30; n++ {
+		g := NewGame(Direction(n%2), Direction(n/2%2))
+		for len(g.GenerateMoves()) > 6 && !g.IsGameOver() {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if g.IsGameOver() {
+			continue
+		}
+		canWin := false
+		for _, mv := range g.GenerateMoves() {
+			_, res, _ := Play(g, mv)
+			canWin = canWin || res.Winner() == g.CurrentPlayer
+		}
+		for l := Beginner; l <= Perfect; l++ {
+			pick := func() Move {
+				s := NewSearcherWith(Options{TTBits: 12, Rand: rand.New(rand.NewSource(int64(n)))})
+				return s.LevelMove(g, l)
+			}
+			mv := pick()
+			if !g.Board.IsEmpty(mv.Row, mv.Col) {
+				t.Fatalf("%v played illegal move %v\n%s", l, mv, g.Board)
+			}
+			if again := pick(); again != mv {
+				t.Fatalf("%v with the same seed played %v, then %v", l, mv, again)
+			}
+			if l >= Hard && canWin {
+				next, res, _ := Play(g, mv)
+				if res.Winner() == g.CurrentPlayer {
+					continue
+				}
+				if r, _ := bruteForce(next); res.Over() || r != -1 {
+					t.Fatalf("%v gave up a win with %v\n%s", l, mv, g.Board)
+				}
+			}
+		}
+	}
+}
+
+// TestLevelPlayerCancel 取消 ctx 后 LevelPlayer 尽快返回 ctx.Err()。
+func TestLevelPlayerCancel(t *testing.T) {
+	g := NewGame(Clockwise, CounterClockwise)
+	p := NewLevelPlayer(Options{TTBits: 12, Rand: rand.New(rand.NewSource(1))}, Medium)
+	mv, err := p.ChooseMove(context.Background(), g)
+	if err != nil || !g.Board.IsEmpty(mv.Row, mv.Col) {
+		t.Fatalf("ChooseMove = %v, %v", mv, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Level = Perfect
+	if _, err := p.ChooseMove(ctx, g); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ChooseMove after cancel: err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,120 @@
+// File game/ordering.go
+package game
+
+import "fmt"
+
+/* ---------- 着法排序 ---------- */
+
+// Ordering 选择内部结点的着法排序方式（根结点总是随机打乱后把上一层的最佳着法排在最前）。
+type Ordering int
+
+const (
+	// OrderFull 依次为：置换表着法、堵住对手威胁的着法、杀手着法，其余按历史表得分
+	// （一步取胜的局面在 negamax 中直接返回，见 tactics.go）。
+	OrderFull Ordering = iota
+	// OrderTT 只把置换表着法排到最前，其余保持 GenerateMoves 的顺序，也不做 tactics 的取胜截断与剪枝，
+	// 即加入着法排序之前的搜索，用于对比结点数。
+	OrderTT
+)
+
+var orderingNames = [...]string{"full", "tt"}
+
+// String 返回排序方式的名称（即 ParseOrdering 接受的写法）。
+func (o Ordering) String() string {
+	if o < 0 || int(o) >= len(orderingNames) {
+		return fmt.Sprintf("Ordering(%d)", int(o))
+	}
+	return orderingNames[o]
+}
+
+// ParseOrdering 解析排序方式名称：full 或 tt。
+func ParseOrdering(s string) (Ordering, error) {
+	for i, name := range orderingNames {
+		if s == name {
+			return Ordering(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown move ordering %q (want full or tt)", s)
+}
+
+// 排序分值：各类着法依次递减，历史表得分总小于 orderKiller2
+const (
+	orderTT      = 1 << 40
+	orderBlock   = 1 << 38 // 加上历史表得分
+	orderKiller1 = 1 << 37
+	orderKiller2 = 1 << 36
+	historyMax   = 1 << 35 // 超过时把历史表整体减半
+)
+
+// orderMoves 按 s.ordering 就地排序 moves；ttMove 为置换表记录的最佳着法（可为 noMove），
+// blocks 为 tactics 给出的堵截着法。
+func (s *Searcher) orderMoves(gs *GameState, moves []Move, ttMove int8, blocks uint16, ply int) {
+	if s.ordering == OrderTT {
+		if ttMove != noMove {
+			for i, mv := range moves {
+				if int8(mv.Row*4+mv.Col) == ttMove {
+					moves[0], moves[i] = moves[i], moves[0]
+					break
+				}
+			}
+		}
+		return
+	}
+
+	hist := &s.history[gs.CurrentPlayer]
+	killers := s.killers[ply]
+	var keys [16]int64
+	for i, mv := range moves {
+		sq := int8(mv.Row*4 + mv.Col)
+		bit := uint16(1) << sq
+		switch {
+		case sq == ttMove:
+			keys[i] = orderTT
+		case blocks&bit != 0:
+			keys[i] = orderBlock + hist[sq]
+		case sq == killers[0]:
+			keys[i] = orderKiller1
+		case sq == killers[1]:
+			keys[i] = orderKiller2
+		default:
+			keys[i] = hist[sq]
+		}
+	}
+	// 着法至多 16 个，插入排序即可；相同分值保持原顺序
+	for i := 1; i < len(moves); i++ {
+		for j := i; j > 0 && keys[j] > keys[j-1]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+			moves[j], moves[j-1] = moves[j-1], moves[j]
+		}
+	}
+}
+
+// recordCutoff 记录在 ply 层引起 β 剪枝的着法：更新杀手着法与历史表。
+func (s *Searcher) recordCutoff(gs *GameState, mv Move, depth, ply int) {
+	if s.ordering == OrderTT {
+		return
+	}
+	sq := int8(mv.Row*4 + mv.Col)
+	if k := &s.killers[ply]; k[0] != sq {
+		k[0], k[1] = sq, k[0]
+	}
+	hist := &s.history[gs.CurrentPlayer]
+	hist[sq] += int64(depth * depth)
+	if hist[sq] >= historyMax {
+		for i := range hist {
+			hist[i] /= 2
+		}
+	}
+}
+
+// resetOrdering 在每次 Search 开始时清空杀手着法，并让历史表衰减
+func (s *Searcher) resetOrdering() {
+	for i := range s.killers {
+		s.killers[i] = [2]int8{noMove, noMove}
+	}
+	for c := range s.history {
+		for i := range s.history[c] {
+			s.history[c][i] /= 8
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// randomPosition 从空棋盘随机走 plies 步，对局提前结束时返回 nil。
+func randomPosition(rng *rand.Rand, plies int) *GameState {
+	g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+	for i := 0; i < plies; i++ {
+		moves := g.GenerateMoves()
+		mv := moves[rng.Intn(len(moves))]
+		_ = g.ApplyMove(mv.Row, mv.Col)
+		if g.IsGameOver() {
+			return nil
+		}
+	}
+	return g
+}
+
+// canWin 返回走棋方是否有一步即胜的着法（穷举）。
+func canWin(g *GameState) bool {
+	for _, mv := range g.GenerateMoves() {
+		if _, res, _ := Play(g, mv); res.Winner() == g.CurrentPlayer {
+			return true
+		}
+	}
+	return false
+}
+
+// TestOrderingReducesNodes 完整排序在固定深度下访问的结点数少于只用置换表着法的排序。
+func TestOrderingReducesNodes(t *testing.T) {
+	var nodes [2]int64
+	for _, g := range benchPositions() {
+		for _, o := range []Ordering{OrderFull, OrderTT} {
+			s := NewSearcherWith(Options{TTBits: 16, Rand: rand.New(rand.NewSource(1)), Order: o})
+			res := s.Search(context.Background(), g, Limits{Depth: 6}, nil)
+			nodes[o] += res.Stats.Nodes
+		}
+	}
+	if nodes[OrderFull] >= nodes[OrderTT] {
+		t.Fatalf("full ordering searched %d nodes, tt ordering %d", nodes[OrderFull], nodes[OrderTT])
+	}
+	t.Logf("nodes: full %d, tt %d", nodes[OrderFull], nodes[OrderTT])
+}
+
+func TestParseOrdering(t *testing.T) {
+	for _, o := range []Ordering{OrderFull, OrderTT} {
+		if got, err := ParseOrdering(o.String()); err != nil || got != o {
+			t.Fatalf("ParseOrdering(%q) = %v, %v", o, got, err)
+		}
+	}
+	if _, err := ParseOrdering("best"); err == nil {
+		t.Fatal("ParseOrdering accepted an unknown name")
+	}
+}
@@ -0,0 +1,115 @@
+// File game/parallel.go
+package game
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+/* ---------- 多线程根结点拆分 ---------- */
+
+// 并行搜索把根结点的着法分给多个线程：先由主线程搜完第一个（通常是上一层的最佳）着法
+// 得到一个好的 α，其余着法由各线程按顺序领取。各线程有自己的置换表，通过共享的 α
+// 互相收紧窗口。每个着法都以 α-1 为下界搜索，分数等于最佳分的着法总能得到精确值，
+// 因此与串行搜索一样选出“着法顺序中第一个取得最高分”的着法。
+
+// sharedStop 为并行搜索中各线程共用的中止状态。
+type sharedStop struct {
+	nodes   atomic.Int64 // 所有线程累计的结点数（每 1024 个结点汇总一次）
+	stopped atomic.Bool
+}
+
+// helperSearchers 返回 n-1 个辅助搜索器，按需创建并在多次搜索间复用（保留各自的置换表）。
+func (s *Searcher) helperSearchers(n int) []*Searcher {
+	for len(s.helpers) < n-1 {
+		s.helpers = append(s.helpers, &Searcher{tt: NewTransTable(s.tt.bits()), eval: s.eval, weights: s.weights, ordering: s.ordering})
+	}
+	return s.helpers[:n-1]
+}
+
+// parallelRootSearch 是 rootSearch 的多线程版本，主变同样写入 s.pv[0]。
+func (s *Searcher) parallelRootSearch(g *GameState, moves []Move, depth, threads int) (score int, ok bool) {
+	workers := append([]*Searcher{s}, s.helperSearchers(threads)...)
+	for _, w := range workers[1:] {
+		w.stop = s.stop
+		w.ResetStats()
+		w.resetOrdering()
+	}
+
+	scores := make([]int, len(moves))
+	pvs := make([][]Move, len(moves))
+	var alpha atomic.Int64
+	alpha.Store(loseScore)
+
+	// searchMove 由 w 搜索第 i 个着法，记录分数与主变并抬高共享的 α。
+	searchMove := func(w *Searcher, i int) bool {
+		a := int(alpha.Load())
+		sc := w.searchChild(g, moves[i], depth, 0, a-1, winScore)
+		if w.stop.stopped {
+			return false
+		}
+		w.updatePV(0, moves[i])
+		scores[i] = sc
+		pvs[i] = append([]Move(nil), w.pv[0][:w.pvLen[0]]...)
+		for {
+			cur := alpha.Load()
+			if int64(sc) <= cur || alpha.CompareAndSwap(cur, int64(sc)) {
+				break
+			}
+		}
+		return true
+	}
+
+	ok = searchMove(s, 0)
+	if ok {
+		var next atomic.Int64
+		next.Store(1)
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func(w *Searcher) {
+				defer wg.Done()
+				for {
+					i := int(next.Add(1) - 1)
+					if i >= len(moves) || !searchMove(w, i) {
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		ok = !s.stop.shared.stopped.Load()
+	}
+	for _, w := range workers[1:] {
+		s.stats.add(w.stats)
+	}
+	if !ok {
+		s.stop.stopped = true
+		return 0, false
+	}
+
+	best := 0
+	for i, sc := range scores {
+		if sc > scores[best] {
+			best = i
+		}
+	}
+	s.pvLen[0] = copy(s.pv[0][:], pvs[best])
+	return scores[best], true
+}
+
+// add 把另一个搜索器的统计累加进来。
+func (st *SearchStats) add(o SearchStats) {
+	st.Nodes += o.Nodes
+	st.TTProbes += o.TTProbes
+	st.TTHits += o.TTHits
+	st.TTCutoffs += o.TTCutoffs
+	st.Cutoffs += o.Cutoffs
+	st.FirstCuts += o.FirstCuts
+}
+
+// bits 返回置换表大小的以 2 为底的对数。
+func (t *TransTable) bits() int {
+	return int(math.Log2(float64(len(t.entries))))
+}
@@ -0,0 +1,103 @@
+// File game/player.go
+package game
+
+import (
+	"context"
+	"errors"
+)
+
+/* ---------- 对局者 ---------- */
+
+// Player 为一方的着法来源：人类输入、引擎、脚本或远程对手。
+// ChooseMove 收到的是局面副本，可以随意修改；ctx 取消时应尽快返回。
+type Player interface {
+	ChooseMove(ctx context.Context, g *GameState) (Move, error)
+}
+
+// HumanPlayer 由需要人类输入的 Player 实现。
+// 悔棋与重做时 Driver 会一直撤销（重做）到轮到这样的 Player 为止。
+type HumanPlayer interface {
+	Player
+	IsHuman() bool
+}
+
+var (
+	// ErrUndo 与 ErrRedo 由人类 Player 返回，请求 Driver 悔棋或重做后再次询问。
+	ErrUndo = errors.New("game: undo requested")
+	ErrRedo = errors.New("game: redo requested")
+
+	// ErrScriptExhausted 表示 ScriptedPlayer 的着法已经用完。
+	ErrScriptExhausted = errors.New("game: scripted player has no moves left")
+)
+
+// PlayerFunc 把普通函数适配为 Player。
+type PlayerFunc func(ctx context.Context, g *GameState) (Move, error)
+
+// ChooseMove 实现 Player。
+func (f PlayerFunc) ChooseMove(ctx context.Context, g *GameState) (Move, error) {
+	return f(ctx, g)
+}
+
+// EnginePlayer 用 Searcher 的迭代加深搜索选着。
+type EnginePlayer struct {
+	Searcher *Searcher
+	Limits   Limits
+	Info     func(SearchInfo)   // 每完成一层调用一次，可为 nil
+	Done     func(SearchResult) // 每次搜索结束后调用，可为 nil
+}
+
+// NewEnginePlayer 创建以 lim 为预算、按 opts 新建搜索器的引擎 Player。
+// opts.Rand 取固定种子且单线程搜索时，对局可以复现。
+func NewEnginePlayer(opts Options, lim Limits) *EnginePlayer {
+	return &EnginePlayer{Searcher: NewSearcherWith(opts), Limits: lim}
+}
+
+// ChooseMove 实现 Player。
+func (p *EnginePlayer) ChooseMove(ctx context.Context, g *GameState) (Move, error) {
+	res := p.Searcher.Search(ctx, g, p.Limits, p.Info)
+	if p.Done != nil {
+		p.Done(res)
+	}
+	if res.Move.Row < 0 {
+		return res.Move, errors.New("game: no legal move")
+	}
+	return res.Move, nil
+}
+
+// LevelPlayer 按难度等级选着（见 Searcher.LevelMove）。
+type LevelPlayer struct {
+	Searcher *Searcher
+	Level    Level
+}
+
+// NewLevelPlayer 创建难度为 level、按 opts 新建搜索器的 Player。
+func NewLevelPlayer(opts Options, level Level) *LevelPlayer {
+	return &LevelPlayer{Searcher: NewSearcherWith(opts), Level: level}
+}
+
+// ChooseMove 实现 Player；ctx 取消时返回 ctx.Err()。
+func (p *LevelPlayer) ChooseMove(ctx context.Context, g *GameState) (Move, error) {
+	mv, ok := p.Searcher.levelMove(ctx, g, p.Level)
+	switch {
+	case !ok:
+		return mv, ctx.Err()
+	case mv.Row < 0:
+		return mv, errors.New("game: no legal move")
+	}
+	return mv, nil
+}
+
+// ScriptedPlayer 依次走出预先给定的着法，用于测试与复盘。
+type ScriptedPlayer struct {
+	Moves []Move
+	next  int
+}
+
+// ChooseMove 实现 Player；着法用完时返回 ErrScriptExhausted。
+func (p *ScriptedPlayer) ChooseMove(ctx context.Context, g *GameState) (Move, error) {
+	if p.next >= len(p.Moves) {
+		return Move{}, ErrScriptExhausted
+	}
+	p.next++
+	return p.Moves[p.next-1], nil
+}
@@ -0,0 +1,200 @@
+// File game/position.go
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"trackLogicChess/internal/player"
+)
+
+// 局面记法（类似国际象棋的 FEN），由空格分隔的 5 个字段组成：
+//
+//	棋盘 走棋方 外圈方向 内圈方向 结果
+//
+//   - 棋盘：自上而下 4 行，以 '/' 分隔；每行自左向右，'b' 为黑子、'w' 为白子，
+//     数字 1–4 表示连续的空格数，每行合计 4 格。
+//   - 走棋方：'b' 或 'w'。对局已结束时为走出最后一步的一方（与 GameState.CurrentPlayer 一致）。
+//   - 外圈方向、内圈方向：'cw'（顺时针）或 'ccw'（逆时针）。
+//   - 结果：'-' 未结束，'b' 黑胜，'w' 白胜，'d' 平局。
+//
+// 初始局面为 "4/4/4/4 b cw cw -"（两圈均顺时针）。
+
+// ErrBadPosition 为局面记法格式错误，具体原因以包装的形式给出。
+var ErrBadPosition = errors.New("game: malformed position")
+
+// StartPosition 返回给定旋转配置下初始局面的记法。
+func StartPosition(dirOuter, dirInner Direction) string {
+	return FormatPosition(NewGame(dirOuter, dirInner))
+}
+
+// FormatPosition 返回局面 g 的记法。
+func FormatPosition(g *GameState) string {
+	var sb strings.Builder
+	sb.WriteString(FormatBoard(g.Board))
+	sb.WriteByte(' ')
+	sb.WriteByte(colorChar(g.CurrentPlayer))
+	fmt.Fprintf(&sb, " %s %s ", g.DirOuter, g.DirInner)
+	switch res := g.Result(); res {
+	case Ongoing:
+		sb.WriteByte('-')
+	case Draw:
+		sb.WriteByte('d')
+	default:
+		sb.WriteByte(colorChar(res.Winner()))
+	}
+	return sb.String()
+}
+
+// FormatBoard 返回记法中的棋盘字段，例如 "4/1b2/4/4"。
+func FormatBoard(b *Board) string {
+	var sb strings.Builder
+	for r := 0; r < 4; r++ {
+		if r > 0 {
+			sb.WriteByte('/')
+		}
+		empty := 0
+		for c := 0; c < 4; c++ {
+			col := b.Cell(r, c)
+			if col == player.Empty {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			sb.WriteByte(colorChar(col))
+		}
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+		}
+	}
+	return sb.String()
+}
+
+// ParsePosition 解析局面记法。除格式外还校验局面自洽：
+// 子数与走棋方相符，结果字段与按 Outcome 判定的结果一致。
+func ParsePosition(s string) (*GameState, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: want 5 fields, got %d", ErrBadPosition, len(fields))
+	}
+
+	b, err := ParseBoard(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	side, ok := charColor(fields[1])
+	if !ok {
+		return nil, fmt.Errorf("%w: side to move %q, want b or w", ErrBadPosition, fields[1])
+	}
+	dirOuter, err := ParseDirection(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: outer %v", ErrBadPosition, err)
+	}
+	dirInner, err := ParseDirection(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w: inner %v", ErrBadPosition, err)
+	}
+	var res Result
+	switch fields[4] {
+	case "-":
+		res = Ongoing
+	case "b":
+		res = BlackWins
+	case "w":
+		res = WhiteWins
+	case "d":
+		res = Draw
+	default:
+		return nil, fmt.Errorf("%w: result %q, want -, b, w or d", ErrBadPosition, fields[4])
+	}
+
+	// 黑先手：轮到黑走时两方子数相等，轮到白走时黑多一子；
+	// 对局结束后走棋方为最后落子的一方，关系正好相反。
+	nb, nw := bits.OnesCount16(b.black), bits.OnesCount16(b.white)
+	lastMover := side.Opponent()
+	if res.Over() {
+		lastMover = side
+	}
+	switch {
+	case lastMover == player.Black && nb != nw+1,
+		lastMover == player.White && nb != nw:
+		return nil, fmt.Errorf("%w: %d black and %d white pieces with %s to move", ErrBadPosition, nb, nw, fields[1])
+	}
+	if nb+nw > 0 {
+		if got := Outcome(b, lastMover); got != res {
+			return nil, fmt.Errorf("%w: result field %s, board is %s", ErrBadPosition, res, got)
+		}
+	} else if res.Over() {
+		return nil, fmt.Errorf("%w: empty board cannot be finished", ErrBadPosition)
+	}
+
+	g := &GameState{
+		Board:         b,
+		CurrentPlayer: side,
+		DirOuter:      dirOuter,
+		DirInner:      dirInner,
+		Winner:        res.Winner(),
+		GameOver:      res.Over(),
+	}
+	g.Rehash()
+	return g, nil
+}
+
+// ParseBoard 解析记法中的棋盘字段（只检查格式，不检查子数）。
+// 连续的空格必须合写成一个数字（例如 "2" 而不是 "11"），因此每个棋盘只有一种写法，与 FormatBoard 一致。
+func ParseBoard(s string) (*Board, error) {
+	rows := strings.Split(s, "/")
+	if len(rows) != 4 {
+		return nil, fmt.Errorf("%w: board has %d rows, want 4", ErrBadPosition, len(rows))
+	}
+	b := NewBoard()
+	for r, row := range rows {
+		c, digit := 0, false // digit：上一个字符是否为数字
+		for _, ch := range row {
+			switch {
+			case ch >= '1' && ch <= '4':
+				if digit {
+					return nil, fmt.Errorf("%w: consecutive digits in row %d", ErrBadPosition, r+1)
+				}
+				c += int(ch - '0')
+			case ch == 'b' || ch == 'w':
+				if c < 4 {
+					col, _ := charColor(string(ch))
+					b.Set(r, c, col)
+				}
+				c++
+			default:
+				return nil, fmt.Errorf("%w: invalid character %q in row %d", ErrBadPosition, ch, r+1)
+			}
+			digit = ch >= '1' && ch <= '4'
+		}
+		if c != 4 {
+			return nil, fmt.Errorf("%w: row %d has %d cells, want 4", ErrBadPosition, r+1, c)
+		}
+	}
+	return b, nil
+}
+
+// colorChar 返回颜色在记法中的字符。
+func colorChar(col player.Color) byte {
+	if col == player.White {
+		return 'w'
+	}
+	return 'b'
+}
+
+// charColor 解析记法中的颜色字段。
+func charColor(s string) (player.Color, bool) {
+	switch s {
+	case "b":
+		return player.Black, true
+	case "w":
+		return player.White, true
+	}
+	return player.Empty, false
+}
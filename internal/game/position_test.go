@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// TestPositionRoundTrip 随机对局中的每个局面（含已结束的）经记法往返后不变。
+func TestPositionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	for n := 0; n < 100; n++ {
+		g := NewGame(Direction(n%2), Direction(n/2%2))
+		for {
+			s := FormatPosition(g)
+			got, err := ParsePosition(s)
+			if err != nil {
+				t.Fatalf("ParsePosition(%q): %v", s, err)
+			}
+			if *got.Board != *g.Board || got.CurrentPlayer != g.CurrentPlayer ||
+				got.DirOuter != g.DirOuter || got.DirInner != g.DirInner ||
+				got.Result() != g.Result() || got.Key() != g.Key() {
+				t.Fatalf("round trip of %q changed the position", s)
+			}
+			if FormatPosition(got) != s {
+				t.Fatalf("FormatPosition(ParsePosition(%q)) = %q", s, FormatPosition(got))
+			}
+			if g.IsGameOver() {
+				break
+			}
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+	if s := StartPosition(Clockwise, CounterClockwise); s != "4/4/4/4 b cw ccw -" {
+		t.Errorf("StartPosition = %q", s)
+	}
+}
+
+func TestParsePositionErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"4/4/4/4 b cw cw",         // 字段不足
+		"4/4/4 b cw cw -",         // 行数不足
+		"4/4/4/5 b cw cw -",       // 数字越界
+		"4/4/4/3 b cw cw -",       // 行内格数不足
+		"bbbbb/4/4/4 b cw cw -",   // 行内格数过多
+		"4/4/4/x3 b cw cw -",      // 非法字符
+		"1111/4/4/4 b cw cw -",    // 连续数字：空格应合写为 4
+		"4/22/4/4 b cw cw -",      // 连续数字
+		"4/4/13/4 b cw cw -",      // 连续数字
+		"4/4/4/b21 w cw cw -",     // 连续数字
+		"4/4/4/4 x cw cw -",       // 走棋方
+		"4/4/4/4 b up cw -",       // 方向
+		"4/4/4/4 b cw cw ?",       // 结果
+		"b3/4/4/4 b cw cw -",      // 子数与走棋方不符
+		"4/4/4/4 b cw cw d",       // 空棋盘不能结束
+		"bbbb/ww2/w3/4 w cw cw -", // 黑已连 4 却标为未结束
+		"bbb1/ww2/4/4 b cw cw b",  // 未连 4 却标为黑胜
+	} {
+		if _, err := ParsePosition(s); !errors.Is(err, ErrBadPosition) {
+			t.Errorf("ParsePosition(%q) error = %v, want ErrBadPosition", s, err)
+		}
+	}
+}
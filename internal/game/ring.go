@@ -1,90 +1,112 @@
 // File game/ring.go
 package game
 
-import (
-	"trackLogicChess/internal/player"
-)
-
-// RotateOuter 对 4×4 棋盘的外圈 12 个格子执行“环移”一格操作。
 // 外圈坐标（顺时针顺序）为：
 //
 //	(0,0) → (0,1) → (0,2) → (0,3) → (1,3) → (2,3) → (3,3) → (3,2) → (3,1) → (3,0) → (2,0) → (1,0) → (0,0)
 //
-// 如果 dir == Clockwise，则每个格子向下一个位置（顺时针方向）移动；
-// 如果 dir == CounterClockwise，则向上一个位置（逆时针方向）移动。
-func RotateOuter(b *Board, dir Direction) {
-	// 按顺时针顺序列出外圈坐标
-	coords := [][2]int{
-		{0, 0}, {0, 1}, {0, 2}, {0, 3},
-		{1, 3}, {2, 3},
-		{3, 3}, {3, 2}, {3, 1}, {3, 0},
-		{2, 0}, {1, 0},
-	}
+// 内圈坐标（顺时针顺序）为：
+//
+//	(1,1) → (1,2) → (2,2) → (2,1) → (1,1)
+//
+// 下面以位下标 r*4+c 记录两圈格子。
+var (
+	outerRing = [12]uint8{0, 1, 2, 3, 7, 11, 15, 14, 13, 12, 8, 4}
+	innerRing = [4]uint8{5, 6, 10, 9}
+)
 
-	// 将当前外圈所有格子的值依次存入 vals
-	var vals []player.Color
-	for _, rc := range coords {
-		r, c := rc[0], rc[1]
-		vals = append(vals, b.Get(r, c))
-	}
+// permTable 是一张按字节查表的位置换表：
+// 把 16 位掩码拆成高低两个字节，分别查表后按位或即得置换结果。
+type permTable [2][256]uint16
+
+// apply 返回掩码 m 经过置换后的结果。
+func (t *permTable) apply(m uint16) uint16 {
+	return t[0][m&0xFF] | t[1][m>>8]
+}
 
-	n := len(vals) // n == 12
-	if n == 0 {
-		return
+// newPermTable 根据 dst（dst[i] 为第 i 位棋子移动后的位下标）生成查表。
+func newPermTable(dst [16]uint8) permTable {
+	var t permTable
+	for half := 0; half < 2; half++ {
+		for v := 0; v < 256; v++ {
+			var out uint16
+			for bit := 0; bit < 8; bit++ {
+				if v&(1<<bit) != 0 {
+					out |= 1 << dst[half*8+bit]
+				}
+			}
+			t[half][v] = out
+		}
 	}
+	return t
+}
 
-	// 根据 dir 计算新位置时的偏移量
-	// 顺时针：当前位置 i 的新值来源于旧位置 (i-1+n)%n
-	// 逆时针：当前位置 i 的新值来源于旧位置 (i+1)%n
-	for i, rc := range coords {
-		var srcIdx int
+// ringShift 把环 ring 上的棋子按 dir 方向移动一格，写入 dst。
+// 顺时针：位于 ring[i] 的棋子移动到 ring[i+1]；逆时针则移动到 ring[i-1]。
+func ringShift(dst *[16]uint8, ring []uint8, dir Direction) {
+	n := len(ring)
+	for i, from := range ring {
 		if dir == Clockwise {
-			srcIdx = (i - 1 + n) % n
+			dst[from] = ring[(i+1)%n]
 		} else {
-			// CounterClockwise
-			srcIdx = (i + 1) % n
+			dst[from] = ring[(i-1+n)%n]
 		}
-		r, c := rc[0], rc[1]
-		b.Set(r, c, vals[srcIdx])
 	}
 }
 
-// RotateInner 对 4×4 棋盘的内圈 4 个格子执行“旋转”一格操作。
-// 内圈坐标（顺时针顺序）为：
-//
-//	(1,1) → (1,2) → (2,2) → (2,1) → (1,1)
-//
-// 如果 dir == Clockwise，则每个格子向下一个位置（顺时针方向）移动；
-// 如果 dir == CounterClockwise，则向上一个位置（逆时针方向）移动。
-func RotateInner(b *Board, dir Direction) {
-	// 按顺时针顺序列出内圈坐标
-	coords := [][2]int{
-		{1, 1}, {1, 2},
-		{2, 2}, {2, 1},
+// identityPerm 返回不移动任何棋子的置换。
+func identityPerm() [16]uint8 {
+	var p [16]uint8
+	for i := range p {
+		p[i] = uint8(i)
 	}
+	return p
+}
 
-	// 将当前内圈所有格子的值依次存入 vals
-	var vals []player.Color
-	for _, rc := range coords {
-		r, c := rc[0], rc[1]
-		vals = append(vals, b.Get(r, c))
-	}
+var (
+	outerPerm [2]permTable    // outerPerm[dir]：仅外圈旋转
+	innerPerm [2]permTable    // innerPerm[dir]：仅内圈旋转
+	bothPerm  [2][2]permTable // bothPerm[dirOuter][dirInner]：外圈与内圈同时旋转
+)
 
-	n := len(vals) // n == 4
-	if n == 0 {
-		return
-	}
+func init() {
+	for _, d := range []Direction{Clockwise, CounterClockwise} {
+		p := identityPerm()
+		ringShift(&p, outerRing[:], d)
+		outerPerm[d] = newPermTable(p)
 
-	// 根据 dir 计算新位置时的偏移量
-	for i, rc := range coords {
-		var srcIdx int
-		if dir == Clockwise {
-			srcIdx = (i - 1 + n) % n
-		} else {
-			// CounterClockwise
-			srcIdx = (i + 1) % n
+		p = identityPerm()
+		ringShift(&p, innerRing[:], d)
+		innerPerm[d] = newPermTable(p)
+	}
+	for _, do := range []Direction{Clockwise, CounterClockwise} {
+		for _, di := range []Direction{Clockwise, CounterClockwise} {
+			p := identityPerm()
+			ringShift(&p, outerRing[:], do)
+			ringShift(&p, innerRing[:], di)
+			bothPerm[do][di] = newPermTable(p)
 		}
-		r, c := rc[0], rc[1]
-		b.Set(r, c, vals[srcIdx])
 	}
 }
+
+// RotateOuter 对 4×4 棋盘的外圈 12 个格子执行“环移”一格操作。
+// 如果 dir == Clockwise，则每个格子向下一个位置（顺时针方向）移动；
+// 如果 dir == CounterClockwise，则向上一个位置（逆时针方向）移动。
+func RotateOuter(b *Board, dir Direction) {
+	t := &outerPerm[dir]
+	b.black, b.white = t.apply(b.black), t.apply(b.white)
+}
+
+// RotateInner 对 4×4 棋盘的内圈 4 个格子执行“旋转”一格操作。
+// 如果 dir == Clockwise，则每个格子向下一个位置（顺时针方向）移动；
+// 如果 dir == CounterClockwise，则向上一个位置（逆时针方向）移动。
+func RotateInner(b *Board, dir Direction) {
+	t := &innerPerm[dir]
+	b.black, b.white = t.apply(b.black), t.apply(b.white)
+}
+
+// Rotate 一次性完成外圈与内圈的旋转，等价于依次调用 RotateOuter 与 RotateInner。
+func Rotate(b *Board, dirOuter, dirInner Direction) {
+	t := &bothPerm[dirOuter][dirInner]
+	b.black, b.white = t.apply(b.black), t.apply(b.white)
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"testing"
+
+	"trackLogicChess/internal/player"
+)
+
+// rotateRef 按坐标逐格环移，作为查表实现的参照。
+func rotateRef(b *Board, coords [][2]int, dir Direction) {
+	n := len(coords)
+	vals := make([]player.Color, n)
+	for i, rc := range coords {
+		vals[i] = b.Get(rc[0], rc[1])
+	}
+	for i, rc := range coords {
+		src := (i + 1) % n
+		if dir == Clockwise {
+			src = (i - 1 + n) % n
+		}
+		b.Set(rc[0], rc[1], vals[src])
+	}
+}
+
+func TestRotateMatchesReference(t *testing.T) {
+	outer := [][2]int{
+		{0, 0}, {0, 1}, {0, 2}, {0, 3},
+		{1, 3}, {2, 3},
+		{3, 3}, {3, 2}, {3, 1}, {3, 0},
+		{2, 0}, {1, 0},
+	}
+	inner := [][2]int{{1, 1}, {1, 2}, {2, 2}, {2, 1}}
+
+	// 用一个简单的线性同余序列生成若干随机棋盘
+	seed := uint32(12345)
+	for n := 0; n < 500; n++ {
+		b := NewBoard()
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 4; c++ {
+				seed = seed*1103515245 + 12345
+				b.Set(r, c, player.Color(seed>>16%3))
+			}
+		}
+		for _, do := range []Direction{Clockwise, CounterClockwise} {
+			for _, di := range []Direction{Clockwise, CounterClockwise} {
+				want := b.Clone()
+				rotateRef(want, outer, do)
+				rotateRef(want, inner, di)
+
+				got := b.Clone()
+				Rotate(got, do, di)
+				if *got != *want {
+					t.Fatalf("Rotate(%v,%v) of\n%s\ngot\n%s\nwant\n%s", do, di, b, got, want)
+				}
+
+				got = b.Clone()
+				RotateOuter(got, do)
+				RotateInner(got, di)
+				if *got != *want {
+					t.Fatalf("RotateOuter+RotateInner(%v,%v) of\n%s\ngot\n%s\nwant\n%s", do, di, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBoardIndexOutOfRange(t *testing.T) {
+	for _, rc := range [][2]int{{-1, 0}, {0, -1}, {4, 0}, {0, 4}, {1, 5}, {5, 1}} {
+		b := NewBoard()
+		if b.Get(rc[0], rc[1]) != player.Empty || b.IsEmpty(rc[0], rc[1]) {
+			t.Fatalf("Get/IsEmpty(%d, %d) should treat the cell as off-board", rc[0], rc[1])
+		}
+		for name, f := range map[string]func(){
+			"Set":  func() { b.Set(rc[0], rc[1], player.Black) },
+			"Cell": func() { b.Cell(rc[0], rc[1]) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("%s(%d, %d) did not panic", name, rc[0], rc[1])
+					}
+				}()
+				f()
+			}()
+		}
+		if bl, wh := b.Masks(); bl != 0 || wh != 0 {
+			t.Fatalf("Set(%d, %d) changed the board", rc[0], rc[1])
+		}
+	}
+}
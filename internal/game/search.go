@@ -0,0 +1,168 @@
+// File game/search.go
+package game
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+/* ---------- 迭代加深 ---------- */
+
+// maxPly 为一局中最多的步数（16 格）。
+const maxPly = 16
+
+// Limits 限定一次搜索可用的资源，零值字段表示不限制。
+// 三者都为零时搜索到终局（最多剩余空格数那么深）。
+type Limits struct {
+	Depth    int           // 最大深度
+	MoveTime time.Duration // 时间预算
+	Nodes    int64         // 结点预算
+	Threads  int           // 搜索线程数，≤1 时单线程
+}
+
+// SearchInfo 为迭代加深每完成一层时的报告。
+type SearchInfo struct {
+	Depth   int           // 本层深度
+	Score   int           // 根结点评分（走棋方视角）
+	PV      []Move        // 主要变例
+	Nodes   int64         // 本次搜索累计结点数
+	Elapsed time.Duration // 本次搜索累计用时
+}
+
+// SearchResult 为一次搜索的最终结果，来自最后一个完整完成的迭代。
+type SearchResult struct {
+	Move  Move   // 最佳着法；无合法着法时为 (-1,-1)
+	Score int    // 走棋方视角的评分
+	Depth int    // 完成的深度
+	PV    []Move // 主要变例
+	Stats SearchStats
+}
+
+// stopper 判断搜索是否应当中止：上下文取消、超时或结点数用尽。
+// 多线程搜索时各线程各持一份副本，通过 shared 汇总结点数并互相通知中止。
+type stopper struct {
+	ctx       context.Context
+	deadline  time.Time
+	nodeLimit int64
+	stopped   bool
+	shared    *sharedStop // 单线程时为 nil
+}
+
+// check 每隔一定结点数检查一次中止条件，返回是否已中止。
+func (st *stopper) check(nodes int64) bool {
+	if st.stopped || nodes&1023 != 0 {
+		return st.stopped
+	}
+	if st.shared != nil {
+		if st.shared.stopped.Load() {
+			st.stopped = true
+			return true
+		}
+		nodes = st.shared.nodes.Add(1024)
+	}
+	switch {
+	case st.ctx.Err() != nil,
+		!st.deadline.IsZero() && time.Now().After(st.deadline),
+		st.nodeLimit > 0 && nodes >= st.nodeLimit:
+		st.stopped = true
+		if st.shared != nil {
+			st.shared.stopped.Store(true)
+		}
+	}
+	return st.stopped
+}
+
+// Search 对局面 g 做迭代加深搜索，直到达到 lim 中的任一上限或 ctx 被取消。
+// 每完成一层调用一次 info（可为 nil）。返回最后一个完整完成的迭代给出的着法；
+// 即使第一层就被中止，也会返回一个合法着法。
+func (s *Searcher) Search(ctx context.Context, g *GameState, lim Limits, info func(SearchInfo)) SearchResult {
+	start := time.Now()
+	startNodes := s.stats.Nodes
+	s.stop = stopper{ctx: ctx, nodeLimit: lim.Nodes}
+	s.resetOrdering()
+	if lim.Threads > 1 {
+		s.stop.shared = new(sharedStop) // 结点数从本次搜索开始汇总
+	} else if lim.Nodes > 0 {
+		s.stop.nodeLimit += startNodes
+	}
+	if lim.MoveTime > 0 {
+		s.stop.deadline = start.Add(lim.MoveTime)
+	}
+
+	moves := g.GenerateMoves()
+	res := SearchResult{Move: Move{-1, -1}}
+	if len(moves) == 0 {
+		return res
+	}
+	// 随机打乱，避免评分相同总走同一手
+	s.rng.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+	res.Move = moves[0]
+
+	maxDepth := len(moves)
+	if lim.Depth > 0 && lim.Depth < maxDepth {
+		maxDepth = lim.Depth
+	}
+	for depth := 1; depth <= maxDepth; depth++ {
+		var score int
+		var ok bool
+		if lim.Threads > 1 && len(moves) > 1 {
+			score, ok = s.parallelRootSearch(g, moves, depth, lim.Threads)
+		} else {
+			score, ok = s.rootSearch(g, moves, depth)
+		}
+		if !ok {
+			break
+		}
+		res.Move, res.Score, res.Depth = s.pv[0][0], score, depth
+		res.PV = append([]Move(nil), s.pv[0][:s.pvLen[0]]...)
+		if info != nil {
+			info(SearchInfo{
+				Depth:   depth,
+				Score:   score,
+				PV:      res.PV,
+				Nodes:   s.stats.Nodes - startNodes,
+				Elapsed: time.Since(start),
+			})
+		}
+		if score >= mateBound || score <= -mateBound {
+			break // 胜负已证明，更深的搜索不会改变结果
+		}
+		// 下一层先搜本层的最佳着法
+		for i, mv := range moves {
+			if mv == res.Move {
+				copy(moves[1:i+1], moves[:i])
+				moves[0] = mv
+				break
+			}
+		}
+	}
+	res.Stats = s.stats
+	return res
+}
+
+// rootSearch 以深度 depth 搜索根结点的全部着法，主变写入 s.pv[0]。
+// 搜索被中止时 ok 为 false。
+func (s *Searcher) rootSearch(g *GameState, moves []Move, depth int) (score int, ok bool) {
+	bestScore := math.MinInt
+	alpha := loseScore
+	for _, mv := range moves {
+		score := s.searchChild(g, mv, depth, 0, alpha, winScore)
+		if s.stop.stopped {
+			return 0, false
+		}
+		if score > bestScore {
+			bestScore = score
+			alpha = max(alpha, score)
+			s.updatePV(0, mv)
+		}
+	}
+	return bestScore, true
+}
+
+// updatePV 以 mv 加上 ply+1 层的主变作为 ply 层的新主变。
+func (s *Searcher) updatePV(ply int, mv Move) {
+	s.pv[ply][0] = mv
+	n := copy(s.pv[ply][1:], s.pv[ply+1][:s.pvLen[ply+1]])
+	s.pvLen[ply] = n + 1
+}
@@ -0,0 +1,223 @@
+package game
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestSearchStopsAndReturnsLegalMove 上下文取消或预算用尽时，仍应返回合法着法。
+func TestSearchStopsAndReturnsLegalMove(t *testing.T) {
+	g := NewGame(Clockwise, CounterClockwise)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := NewSearcher(10).Search(ctx, g, Limits{}, nil)
+	if !g.Board.IsEmpty(res.Move.Row, res.Move.Col) {
+		t.Fatalf("cancelled search returned illegal move %v", res.Move)
+	}
+
+	res = NewSearcher(10).Search(context.Background(), g, Limits{Nodes: 5000}, nil)
+	if res.Stats.Nodes > 5000+1024 {
+		t.Fatalf("node budget 5000 exceeded: %d nodes", res.Stats.Nodes)
+	}
+	if !g.Board.IsEmpty(res.Move.Row, res.Move.Col) {
+		t.Fatalf("budgeted search returned illegal move %v", res.Move)
+	}
+
+	start := time.Now()
+	NewSearcher(10).Search(context.Background(), g, Limits{MoveTime: 50 * time.Millisecond}, nil)
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("50ms search took %v", d)
+	}
+}
+
+// TestSearchReportsIterations 每完成一层都应回调一次，深度递增且主变以最佳着法开头。
+func TestSearchReportsIterations(t *testing.T) {
+	g := benchPositions()[0]
+	var infos []SearchInfo
+	res := NewSearcher(16).Search(context.Background(), g, Limits{Depth: 4}, func(info SearchInfo) {
+		infos = append(infos, info)
+	})
+	if len(infos) != 4 || res.Depth != 4 {
+		t.Fatalf("got %d iterations, result depth %d; want 4", len(infos), res.Depth)
+	}
+	for i, info := range infos {
+		if info.Depth != i+1 {
+			t.Errorf("iteration %d reported depth %d", i, info.Depth)
+		}
+		if len(info.PV) == 0 {
+			t.Errorf("iteration %d has empty PV", i)
+		}
+	}
+	if res.PV[0] != res.Move {
+		t.Errorf("PV %v does not start with best move %v", res.PV, res.Move)
+	}
+}
+
+// bruteForce 只借助 ApplyMove 穷举对局，返回走棋方视角的结果
+// （1 胜、0 和、-1 负）及双方最优应对下到终局的步数（胜方求快、负方求慢）。
+func bruteForce(g *GameState) (result, plies int) {
+	result = -2
+	for _, mv := range g.GenerateMoves() {
+		next := g.Clone()
+		if err := next.ApplyMove(mv.Row, mv.Col); err != nil {
+			panic(err)
+		}
+		var r, p int
+		switch {
+		case !next.IsGameOver():
+			r, p = bruteForce(next)
+			r = -r
+		case next.WinnerColor() == g.CurrentPlayer:
+			r = 1
+		case next.WinnerColor() != 0:
+			r = -1
+		}
+		p++
+		better := r > result ||
+			r == result && r == 1 && p < plies ||
+			r == result && r == -1 && p > plies
+		if better {
+			result, plies = r, p
+		}
+	}
+	return result, plies
+}
+
+// expectedScore 把穷举结果换算成搜索应给出的分数。
+func expectedScore(result, plies int) int {
+	switch result {
+	case 1:
+		return winScore - plies
+	case -1:
+		return loseScore + plies
+	}
+	return 0
+}
+
+// TestSearchMatchesBruteForce 在残局上比对搜索与穷举：
+// 胜负步数与请求的深度无关，平局计 0 分，旋转造成的对手连 4 与双方同时连 4 均按 ApplyMove 判定。
+func TestSearchMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	checked := 0
+	for checked < 300 {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		empties := 5 + rng.Intn(4)
+		for len(g.GenerateMoves()) > empties {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if g.IsGameOver() {
+			continue
+		}
+		checked++
+
+		result, plies := bruteForce(g)
+		want := expectedScore(result, plies)
+		for i, depth := range []int{0, empties, empties + 4} {
+			eval := Eval(i % 2) // 两种评估与两种排序交替使用：已证明的胜负与二者无关
+			order := Ordering(checked % 2)
+			res := NewSearcherWith(Options{TTBits: 12, Eval: eval, Order: order}).Search(context.Background(), g, Limits{Depth: depth}, nil)
+			if res.Score != want {
+				t.Fatalf("depth %d, eval %v, ordering %v: score %d, brute force %d (result %d in %d)\n%s",
+					depth, eval, order, res.Score, want, result, plies, g.Board)
+			}
+			next := g.Clone()
+			_ = next.ApplyMove(res.Move.Row, res.Move.Col)
+			got := 0
+			switch {
+			case !next.IsGameOver():
+				r, p := bruteForce(next)
+				got = expectedScore(-r, p+1)
+			case next.WinnerColor() == g.CurrentPlayer:
+				got = winScore - 1
+			case next.WinnerColor() != 0:
+				got = loseScore + 1
+			}
+			if got != want {
+				t.Fatalf("depth %d: move %v is worth %d, best is %d\n%s", depth, res.Move, got, want, g.Board)
+			}
+		}
+	}
+}
+
+// TestParallelSearchMatchesBruteForce 多线程搜索的分数与穷举一致，所选着法的价值也与最佳相同。
+func TestParallelSearchMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	checked := 0
+	for checked < 100 {
+		g := NewGame(Direction(rng.Intn(2)), Direction(rng.Intn(2)))
+		for len(g.GenerateMoves()) > 7 {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if g.IsGameOver() {
+			continue
+		}
+		checked++
+
+		want := expectedScore(bruteForce(g))
+		res := NewSearcher(12).Search(context.Background(), g, Limits{Threads: 4}, nil)
+		if res.Score != want {
+			t.Fatalf("score %d, brute force %d\n%s", res.Score, want, g.Board)
+		}
+		if res.PV[0] != res.Move {
+			t.Fatalf("PV %v does not start with best move %v", res.PV, res.Move)
+		}
+	}
+}
+
+// TestParallelSearchStops 多线程搜索同样遵守结点预算与上下文取消。
+func TestParallelSearchStops(t *testing.T) {
+	g := NewGame(Clockwise, Clockwise)
+	res := NewSearcher(12).Search(context.Background(), g, Limits{Nodes: 20000, Threads: 4}, nil)
+	if res.Stats.Nodes > 20000+4*2048 {
+		t.Fatalf("node budget 20000 exceeded: %d nodes", res.Stats.Nodes)
+	}
+	if !g.Board.IsEmpty(res.Move.Row, res.Move.Col) {
+		t.Fatalf("budgeted search returned illegal move %v", res.Move)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	NewSearcher(12).Search(ctx, g, Limits{Threads: 4}, nil)
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("cancelled search took %v", d)
+	}
+}
+
+// selfPlay 用同一个搜索器下完一整局，返回着法序列。
+func selfPlay(s *Searcher, dirOuter, dirInner Direction) []Move {
+	g := NewGame(dirOuter, dirInner)
+	var moves []Move
+	for !g.IsGameOver() {
+		mv := s.BestMove(g, 3)
+		_ = g.ApplyMove(mv.Row, mv.Col)
+		moves = append(moves, mv)
+	}
+	return moves
+}
+
+// TestSeededSearchIsReproducible 相同种子的两局自对弈逐步相同。
+func TestSeededSearchIsReproducible(t *testing.T) {
+	for seed := int64(1); seed <= 4; seed++ {
+		newSearcher := func() *Searcher {
+			return NewSearcherWith(Options{TTBits: 12, Rand: rand.New(rand.NewSource(seed))})
+		}
+		a := selfPlay(newSearcher(), Clockwise, CounterClockwise)
+		b := selfPlay(newSearcher(), Clockwise, CounterClockwise)
+		if len(a) != len(b) {
+			t.Fatalf("seed %d: games differ:\n%v\n%v", seed, a, b)
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("seed %d: games differ at ply %d:\n%v\n%v", seed, i, a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// File game/session.go
+package game
+
+/* ---------- 悔棋与重做 ---------- */
+
+// Session 包装一局棋的当前局面与着法历史，支持悔棋（Undo）与重做（Redo）。
+// 悔棋通过反向旋转两圈并移走棋子完成，不保存局面副本。
+type Session struct {
+	State *GameState
+	moves []Move // 已走的着法
+	redo  []Move // 被悔掉、可以重做的着法（最后悔掉的在末尾）
+}
+
+// NewSession 以 g 为起始局面创建会话，之后应通过会话而不是直接对 g 走棋。
+func NewSession(g *GameState) *Session {
+	return &Session{State: g}
+}
+
+// Play 走一步棋；成功后清空重做栈。
+func (s *Session) Play(mv Move) error {
+	if err := s.State.ApplyMove(mv.Row, mv.Col); err != nil {
+		return err
+	}
+	s.moves = append(s.moves, mv)
+	s.redo = s.redo[:0]
+	return nil
+}
+
+// Undo 撤销最后一步，返回被撤销的着法；没有可撤销的着法时 ok 为 false。
+func (s *Session) Undo() (mv Move, ok bool) {
+	if len(s.moves) == 0 {
+		return Move{}, false
+	}
+	mv = s.moves[len(s.moves)-1]
+	s.moves = s.moves[:len(s.moves)-1]
+	s.State.undo(mv)
+	s.redo = append(s.redo, mv)
+	return mv, true
+}
+
+// Redo 重走最后一次撤销的着法；没有可重做的着法时 ok 为 false。
+func (s *Session) Redo() (mv Move, ok bool) {
+	if len(s.redo) == 0 {
+		return Move{}, false
+	}
+	mv = s.redo[len(s.redo)-1]
+	if err := s.State.ApplyMove(mv.Row, mv.Col); err != nil {
+		panic("game: redo failed, State was modified outside the session: " + err.Error())
+	}
+	s.redo = s.redo[:len(s.redo)-1]
+	s.moves = append(s.moves, mv)
+	return mv, true
+}
+
+// CanUndo 返回是否有可撤销的着法。
+func (s *Session) CanUndo() bool {
+	return len(s.moves) > 0
+}
+
+// CanRedo 返回是否有可重做的着法。
+func (s *Session) CanRedo() bool {
+	return len(s.redo) > 0
+}
+
+// Moves 返回从起始局面到当前局面的着法序列，调用方不应修改。
+func (s *Session) Moves() []Move {
+	return s.moves
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// sameState 比较两个局面的棋盘、走棋方、胜负状态与键。
+func sameState(a, b *GameState) bool {
+	return *a.Board == *b.Board && a.CurrentPlayer == b.CurrentPlayer &&
+		a.Winner == b.Winner && a.GameOver == b.GameOver && a.Key() == b.Key()
+}
+
+// TestSessionUndoRedo 随机对局中逐步悔棋应依次回到之前的每个局面（含终局），重做则原样恢复。
+func TestSessionUndoRedo(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	for n := 0; n < 200; n++ {
+		s := NewSession(NewGame(Direction(n%2), Direction(n/2%2)))
+		history := []*GameState{s.State.Clone()}
+		for !s.State.IsGameOver() {
+			moves := s.State.GenerateMoves()
+			if err := s.Play(moves[rng.Intn(len(moves))]); err != nil {
+				t.Fatal(err)
+			}
+			history = append(history, s.State.Clone())
+		}
+		final := len(history) - 1
+
+		for i := final - 1; i >= 0; i-- {
+			if _, ok := s.Undo(); !ok {
+				t.Fatalf("Undo failed with %d moves left", i+1)
+			}
+			if !sameState(s.State, history[i]) {
+				t.Fatalf("after undo to ply %d got\n%s\nwant\n%s", i, s.State.Board, history[i].Board)
+			}
+		}
+		if s.CanUndo() {
+			t.Fatal("CanUndo at the start position")
+		}
+		for i := 1; i <= final; i++ {
+			if _, ok := s.Redo(); !ok {
+				t.Fatalf("Redo failed at ply %d", i)
+			}
+			if !sameState(s.State, history[i]) {
+				t.Fatalf("after redo to ply %d got\n%s\nwant\n%s", i, s.State.Board, history[i].Board)
+			}
+		}
+		if s.CanRedo() || len(s.Moves()) != final {
+			t.Fatalf("after redoing everything CanRedo=%v, %d moves", s.CanRedo(), len(s.Moves()))
+		}
+	}
+}
+
+// TestSessionPlayClearsRedo 悔棋后走出新着法，重做栈应清空。
+func TestSessionPlayClearsRedo(t *testing.T) {
+	s := NewSession(NewGame(Clockwise, Clockwise))
+	_ = s.Play(Move{1, 1})
+	_ = s.Play(Move{0, 0})
+	s.Undo()
+	if !s.CanRedo() {
+		t.Fatal("nothing to redo after undo")
+	}
+	_ = s.Play(Move{3, 3})
+	if s.CanRedo() {
+		t.Fatal("redo stack survived a new move")
+	}
+}
@@ -0,0 +1,212 @@
+// File game/symmetry.go
+package game
+
+import (
+	"fmt"
+	"math/bits"
+
+	"trackLogicChess/internal/player"
+)
+
+/* ---------- 棋盘对称 ---------- */
+
+// 4×4 棋盘的 8 个对称（旋转 0°/90°/180°/270°，以及先左右镜像再旋转）都把直线映射为直线、
+// 外圈映射为外圈、内圈映射为内圈。旋转保持两圈的方向，与每步之后的双环旋转可交换，
+// 因此在同一旋转配置下，互为旋转像的局面价值相同；镜像则把顺时针变成逆时针，
+// 把 (外圈, 内圈) 配置映射为两圈方向都相反的配置。
+//
+// 规范形式：在映射到目标配置的对称中，取变换后 (黑子掩码, 白子掩码) 最小的棋盘，
+// 相同时取编号最小的对称。
+
+// Symmetry 表示棋盘的一个对称变换：0–3 为顺时针旋转 Symmetry×90°，
+// 4–7 为先左右镜像、再顺时针旋转 (Symmetry-4)×90°。
+type Symmetry uint8
+
+// NumSymmetries 为棋盘对称的个数。
+const NumSymmetries = 8
+
+// Identity 为恒等变换。
+const Identity Symmetry = 0
+
+var (
+	symCell [NumSymmetries][16]uint8 // symCell[s][i]：格子 i 经 s 变换后的格子
+	symPerm [NumSymmetries]permTable // 掩码的变换查表
+	symInv  [NumSymmetries]Symmetry  // 逆变换
+	symSame [2][2][]Symmetry         // symSame[外圈][内圈]：保持该配置的对称（见 Symmetries）
+)
+
+func init() {
+	for s := Symmetry(0); s < NumSymmetries; s++ {
+		for i := 0; i < 16; i++ {
+			r, c := i/4, i%4
+			if s.Mirrors() {
+				c = 3 - c
+			}
+			for k := 0; k < int(s%4); k++ {
+				r, c = c, 3-r // 顺时针旋转 90°
+			}
+			symCell[s][i] = uint8(r*4 + c)
+		}
+		symPerm[s] = newPermTable(symCell[s])
+	}
+	for s := range symCell {
+		for t := range symCell {
+			if symPerm[t].apply(symPerm[s].apply(0x0002)) == 0x0002 { // 只有恒等变换保持格子 (0,1) 不动
+				symInv[s] = Symmetry(t)
+			}
+		}
+	}
+	for _, do := range []Direction{Clockwise, CounterClockwise} {
+		for _, di := range []Direction{Clockwise, CounterClockwise} {
+			for s := Symmetry(0); s < NumSymmetries; s++ {
+				if o, i := s.Directions(do, di); o == do && i == di {
+					symSame[do][di] = append(symSame[do][di], s)
+				}
+			}
+		}
+	}
+}
+
+var symNames = [NumSymmetries]string{"r0", "r90", "r180", "r270", "m0", "m90", "m180", "m270"}
+
+// String 返回对称的简写：r90 表示顺时针旋转 90°，m90 表示镜像后再旋转 90°。
+func (s Symmetry) String() string {
+	if s >= NumSymmetries {
+		return fmt.Sprintf("Symmetry(%d)", uint8(s))
+	}
+	return symNames[s]
+}
+
+// Mirrors 返回该对称是否包含镜像（镜像会反转两圈的旋转方向）。
+func (s Symmetry) Mirrors() bool {
+	return s >= 4
+}
+
+// Inverse 返回逆变换。
+func (s Symmetry) Inverse() Symmetry {
+	return symInv[s]
+}
+
+// Directions 返回 (dirOuter, dirInner) 配置下的局面经 s 变换后所属的配置。
+func (s Symmetry) Directions(dirOuter, dirInner Direction) (Direction, Direction) {
+	if s.Mirrors() {
+		return dirOuter.Reverse(), dirInner.Reverse()
+	}
+	return dirOuter, dirInner
+}
+
+// Move 返回着法 mv 经 s 变换后的着法。
+func (s Symmetry) Move(mv Move) Move {
+	i := symCell[s][mv.Row*4+mv.Col]
+	return Move{int(i) / 4, int(i) % 4}
+}
+
+// square 变换格子下标（置换表中的着法），noMove 保持不变
+func (s Symmetry) square(sq int8) int8 {
+	if sq == noMove {
+		return noMove
+	}
+	return int8(symCell[s][sq])
+}
+
+// Board 返回棋盘 b 经 s 变换后的新棋盘。
+func (s Symmetry) Board(b *Board) *Board {
+	t := &symPerm[s]
+	return &Board{black: t.apply(b.black), white: t.apply(b.white)}
+}
+
+// Apply 返回局面 g 经 s 变换后的新局面：棋盘按 s 变换，旋转方向按 Directions 映射，
+// 走棋方与胜负状态不变。
+func (s Symmetry) Apply(g *GameState) *GameState {
+	out := g.cloneGameState()
+	out.Board = s.Board(g.Board)
+	out.DirOuter, out.DirInner = s.Directions(g.DirOuter, g.DirInner)
+	out.Rehash()
+	return out
+}
+
+// Symmetries 返回把 (dirOuter, dirInner) 配置映射到自身的对称（即四个旋转，含恒等变换）。
+// 同一配置下互为这些对称像的局面价值相同，最优着法也一一对应。
+func Symmetries(dirOuter, dirInner Direction) []Symmetry {
+	return symSame[dirOuter][dirInner]
+}
+
+// CanonicalDirections 返回配置 (dirOuter, dirInner) 的规范配置：外圈为顺时针，
+// 外圈逆时针的配置经镜像后两圈方向都反转。四种配置因此归并为 (cw, cw) 与 (cw, ccw) 两种。
+func CanonicalDirections(dirOuter, dirInner Direction) (Direction, Direction) {
+	if dirOuter == CounterClockwise {
+		return Clockwise, dirInner.Reverse()
+	}
+	return dirOuter, dirInner
+}
+
+// CanonicalBoard 在把 g 的配置映射到 (dirOuter, dirInner) 的对称中取规范棋盘，
+// 返回该棋盘与所用的对称；没有这样的对称（两种配置不互为镜像）时 ok 为 false。
+func CanonicalBoard(g *GameState, dirOuter, dirInner Direction) (b *Board, sym Symmetry, ok bool) {
+	black, white, sym, ok := canonicalMasks(g.Board, g.DirOuter, g.DirInner, dirOuter, dirInner)
+	if !ok {
+		return nil, 0, false
+	}
+	return &Board{black: black, white: white}, sym, true
+}
+
+// Canonical 返回 g 的规范形式及所用的对称：配置取 CanonicalDirections，棋盘取 CanonicalBoard。
+// 互为对称像的局面（包括不同配置下互为镜像的局面）规范形式相同；
+// g 中的着法 mv 在规范局面中为 sym.Move(mv)，反之用 sym.Inverse().Move。
+func Canonical(g *GameState) (*GameState, Symmetry) {
+	do, di := CanonicalDirections(g.DirOuter, g.DirInner)
+	_, sym, _ := CanonicalBoard(g, do, di)
+	return sym.Apply(g), sym
+}
+
+// IsCanonical 返回黑白掩码为 (black, white) 的棋盘是否为规范棋盘，即在四个旋转像中
+// (黑子掩码, 白子掩码) 最小。CanonicalBoard 的结果总是规范棋盘（镜像配置的局面取镜像像中最小者，
+// 它们恰好构成一个旋转轨道）。不分配内存，供求解器按掩码枚举棋盘时使用。
+func IsCanonical(black, white uint16) bool {
+	v := uint32(black)<<16 | uint32(white)
+	for s := Symmetry(1); s < 4; s++ {
+		t := &symPerm[s]
+		if uint32(t.apply(black))<<16|uint32(t.apply(white)) < v {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalMasks 为 CanonicalBoard 的实现，不分配内存
+func canonicalMasks(b *Board, fromOuter, fromInner, toOuter, toInner Direction) (black, white uint16, sym Symmetry, ok bool) {
+	best := uint32(0)
+	for s := Symmetry(0); s < NumSymmetries; s++ {
+		if o, i := s.Directions(fromOuter, fromInner); o != toOuter || i != toInner {
+			continue
+		}
+		t := &symPerm[s]
+		bl, wh := t.apply(b.black), t.apply(b.white)
+		if v := uint32(bl)<<16 | uint32(wh); !ok || v < best {
+			black, white, sym, best, ok = bl, wh, s, v, true
+		}
+	}
+	return black, white, sym, ok
+}
+
+// symmetryPieces：子数少于此值时置换表才按规范局面取键。子数多时搜索树中几乎
+// 不会出现互为旋转像的局面，计算规范形式只会拖慢搜索。
+const symmetryPieces = 4
+
+// canonicalKey 返回置换表使用的键：子数少于 symmetryPieces 时为 g 在保持其配置的对称下的
+// 规范棋盘的 Zobrist 键，否则为 g 自身的键；另返回从 g 到取键棋盘的对称
+// （置换表中的着法按该棋盘的坐标记录）。同一局面总是得到同一个键。
+func canonicalKey(g *GameState) (uint64, Symmetry) {
+	if bits.OnesCount16(g.Board.occupied()) >= symmetryPieces {
+		return g.key, Identity
+	}
+	black, white, sym, _ := canonicalMasks(g.Board, g.DirOuter, g.DirInner, g.DirOuter, g.DirInner)
+	if sym == Identity {
+		return g.key, sym
+	}
+	key := zobristKey(black, white, g.DirOuter, g.DirInner)
+	if g.CurrentPlayer == player.White {
+		key ^= zobSide
+	}
+	return key, sym
+}
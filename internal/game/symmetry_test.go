@@ -0,0 +1,108 @@
+package game
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// TestSymmetryCommutesWithRotation 对称把直线映射为直线，且“先旋转再变换”
+// 等于“先变换再按映射后的配置旋转”。
+func TestSymmetryCommutesWithRotation(t *testing.T) {
+	lines := make(map[uint16]bool)
+	for _, w := range winMasks {
+		lines[w] = true
+	}
+	rng := rand.New(rand.NewSource(3))
+	for s := Symmetry(0); s < NumSymmetries; s++ {
+		if inv := s.Inverse(); symPerm[inv].apply(symPerm[s].apply(0x1234)) != 0x1234 {
+			t.Fatalf("%v: inverse %v is wrong", s, inv)
+		}
+		for _, w := range winMasks {
+			if !lines[symPerm[s].apply(w)] {
+				t.Fatalf("%v maps line %016b to %016b", s, w, symPerm[s].apply(w))
+			}
+		}
+		for _, do := range []Direction{Clockwise, CounterClockwise} {
+			for _, di := range []Direction{Clockwise, CounterClockwise} {
+				o, i := s.Directions(do, di)
+				for n := 0; n < 50; n++ {
+					m := uint16(rng.Intn(1 << 16))
+					if got, want := symPerm[s].apply(bothPerm[do][di].apply(m)), bothPerm[o][i].apply(symPerm[s].apply(m)); got != want {
+						t.Fatalf("%v under %v/%v: %016b != %016b", s, do, di, got, want)
+					}
+				}
+			}
+		}
+	}
+	for _, do := range []Direction{Clockwise, CounterClockwise} {
+		for _, di := range []Direction{Clockwise, CounterClockwise} {
+			if n := len(Symmetries(do, di)); n != 4 {
+				t.Fatalf("%v/%v: %d symmetries, want 4", do, di, n)
+			}
+		}
+	}
+}
+
+// TestSymmetricPositionsAgree 互为对称像的局面走对应着法后结果相同，穷举价值也相同。
+func TestSymmetricPositionsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for n := 0; n < 200; n++ {
+		g := randomPosition(rng, 8+rng.Intn(3))
+		if g == nil {
+			continue
+		}
+		s := Symmetry(rng.Intn(NumSymmetries))
+		img := s.Apply(g)
+		for _, mv := range g.GenerateMoves() {
+			_, r1, _ := Play(g, mv)
+			_, r2, err := Play(img, s.Move(mv))
+			if err != nil || r1 != r2 {
+				t.Fatalf("%v: move %v gives %v, image gives %v (%v)\n%s", s, mv, r1, r2, err, g.Board)
+			}
+		}
+		if n%10 == 0 {
+			r1, p1 := bruteForce(g)
+			r2, p2 := bruteForce(img)
+			if r1 != r2 || p1 != p2 {
+				t.Fatalf("%v: value %d in %d, image %d in %d\n%s", s, r1, p1, r2, p2, g.Board)
+			}
+		}
+	}
+}
+
+// TestCanonical 一个局面的 8 个对称像规范形式相同；子数较少时置换表键在同一配置的 4 个旋转像间相同；
+// IsCanonical 恰好认出 CanonicalBoard 的结果。
+func TestCanonical(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for n := 0; n < 300; n++ {
+		g := randomPosition(rng, rng.Intn(10))
+		if g == nil {
+			continue
+		}
+		want, _ := Canonical(g)
+		if want.DirOuter != Clockwise {
+			t.Fatalf("canonical outer direction is %v", want.DirOuter)
+		}
+		key, _ := canonicalKey(g)
+		sparse := bits.OnesCount16(g.Board.occupied()) < symmetryPieces
+		for s := Symmetry(0); s < NumSymmetries; s++ {
+			img := s.Apply(g)
+			got, sym := Canonical(img)
+			if got.Board.black != want.Board.black || got.Board.white != want.Board.white ||
+				got.DirInner != want.DirInner || got.Key() != want.Key() {
+				t.Fatalf("%v: canonical form differs\n%s\n%s", s, got.Board, want.Board)
+			}
+			if again := sym.Apply(img); again.Board.black != got.Board.black || again.Board.white != got.Board.white {
+				t.Fatalf("%v: returned symmetry does not reproduce the canonical board", s)
+			}
+			b, _, _ := CanonicalBoard(img, img.DirOuter, img.DirInner)
+			if IsCanonical(img.Board.black, img.Board.white) != (*b == *img.Board) || !IsCanonical(b.black, b.white) {
+				t.Fatalf("%v: IsCanonical disagrees with CanonicalBoard\n%s", s, img.Board)
+			}
+			if k, _ := canonicalKey(img); sparse && !s.Mirrors() && k != key {
+				t.Fatalf("%v: transposition key differs", s)
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// File game/tactics.go
+package game
+
+import (
+	"math/bits"
+	"strings"
+)
+
+/* ---------- 战术分析 ---------- */
+
+// 每步落子之后双环旋转一次，所以“下一手能否连成 4 子”要在旋转后的排列上判断：
+// 走棋方的子在落子后旋转一次（R），对手落子时它们已随两次旋转移动（R²）。
+// 这里在 R 与 R² 的排列上找出成形与差一子的直线，再把相应的格子映射回当前棋盘。
+
+// CellSet 是当前棋盘上的一组格子，第 r*4+c 位表示 (r,c)。
+type CellSet uint16
+
+// Contains 返回着法 mv 的格子是否在集合中。
+func (s CellSet) Contains(mv Move) bool {
+	return s&(1<<(mv.Row*4+mv.Col)) != 0
+}
+
+// Len 返回集合中的格子数。
+func (s CellSet) Len() int {
+	return bits.OnesCount16(uint16(s))
+}
+
+// Moves 按位下标顺序返回集合中的格子。
+func (s CellSet) Moves() []Move {
+	out := make([]Move, 0, s.Len())
+	for m := uint16(s); m != 0; m &= m - 1 {
+		i := bits.TrailingZeros16(m)
+		out = append(out, Move{i / 4, i % 4})
+	}
+	return out
+}
+
+// String 返回形如 "(0,1) (2,3)" 的格子列表，空集为 "-"。
+func (s CellSet) String() string {
+	if s == 0 {
+		return "-"
+	}
+	var parts []string
+	for _, mv := range s.Moves() {
+		parts = append(parts, mv.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// Tactics 为局面对走棋方的一步战术分析，所有格子都是当前棋盘上的落子格。
+type Tactics struct {
+	Wins   CellSet // 落子并旋转后己方连成 4 子而立即取胜
+	Losses CellSet // 落子并旋转后对手连成 4 子而立即落败（旋转替对手完成了直线）
+	Hangs  CellSet // 对局继续，但对手下一手可以取胜
+	Blocks CellSet // 有着法会让对手下一手取胜时，其余不立即结束对局、也不让对手取胜的着法；否则为空
+}
+
+// Tactics 返回 g 的战术分析；对局已结束时各集合均为空。
+func (g *GameState) Tactics() Tactics {
+	if g.GameOver {
+		return Tactics{}
+	}
+	t := tactics(g)
+	return Tactics{Wins: CellSet(t.wins), Losses: CellSet(t.losses), Hangs: CellSet(t.hangs), Blocks: CellSet(t.blocks)}
+}
+
+// tacticSets 为 tactics 的结果（位掩码），见 Tactics 的各字段；
+// draws 为落子后立即和棋（双方同时成线或下满棋盘）的格子。
+type tacticSets struct {
+	wins, losses, hangs, blocks, draws uint16
+}
+
+// lineGaps 返回 mine 中已有 3 子、且 other 未占的直线上的空位；
+// 若 mine 已有成形的直线，full 为 true。
+func lineGaps(mine, other uint16) (gaps uint16, full bool) {
+	for _, w := range winMasks {
+		if other&w != 0 {
+			continue
+		}
+		switch bits.OnesCount16(mine & w) {
+		case 4:
+			full = true
+		case 3:
+			gaps |= w &^ mine
+		}
+	}
+	return gaps, full
+}
+
+// tactics 计算局面 g（未结束）的战术集合。
+func tactics(g *GameState) tacticSets {
+	fwd := &bothPerm[g.DirOuter][g.DirInner]
+	back := &bothPerm[g.DirOuter.Reverse()][g.DirInner.Reverse()]
+	me := g.CurrentPlayer
+	my1, op1 := fwd.apply(g.Board.mask(me)), fwd.apply(g.Board.mask(me.Opponent()))
+	my2, op2 := fwd.apply(my1), fwd.apply(op1)
+	empty := ^g.Board.occupied()
+
+	var t tacticSets
+	// 落子并旋转后（R 排列）：己方成线者胜，对手成线者负，双方都成线为平局
+	myGaps1, myFull1 := lineGaps(my1, op1)
+	_, opFull1 := lineGaps(op1, my1)
+	complete := back.apply(myGaps1) & empty
+	if myFull1 {
+		complete = empty
+	}
+	if opFull1 {
+		t.losses, t.draws = empty&^complete, complete
+		return t
+	}
+	t.wins = complete
+	if bits.OnesCount16(empty) <= 1 {
+		t.draws = empty &^ t.wins // 最后一手，对局随之结束
+		return t
+	}
+
+	// 对手落子并旋转后（R² 排列）：对手成线且己方未成线时对手胜
+	myGaps2, myFull2 := lineGaps(my2, op2)
+	opGaps2, opFull2 := lineGaps(op2, my2)
+	if myFull2 {
+		return t // 对手无论怎么走，己方直线都会成形
+	}
+	quiet := empty &^ t.wins
+	counter := back.apply(back.apply(myGaps2)) // 落在此处使己方直线在 R² 中成形，对手已无法取胜
+	switch {
+	case opFull2:
+		t.hangs = quiet &^ counter
+	case opGaps2 != 0:
+		t.hangs = quiet &^ counter
+		if opGaps2&(opGaps2-1) == 0 { // 只有一个空位时，占住它即可
+			t.hangs &^= back.apply(back.apply(opGaps2))
+		}
+	}
+	if t.hangs != 0 {
+		t.blocks = quiet &^ t.hangs
+	}
+	return t
+}
+
+// pruneMoves 从 moves 中去掉 drop 中的格子；全部会被去掉时原样返回，保证至少有一个着法可走。
+func pruneMoves(moves []Move, drop uint16) []Move {
+	if drop == 0 {
+		return moves
+	}
+	kept := make([]Move, 0, len(moves))
+	for _, mv := range moves {
+		if drop&(1<<(mv.Row*4+mv.Col)) == 0 {
+			kept = append(kept, mv)
+		}
+	}
+	if len(kept) == 0 {
+		return moves
+	}
+	return kept
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestTactics 各集合与逐个试走（对手再逐个试走）的结果一致。
+func TestTactics(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	var seen [4]int
+	for n := 0; n < 4000; n++ {
+		g := randomPosition(rng, 3+rng.Intn(11))
+		if g == nil {
+			continue
+		}
+		tac := g.Tactics()
+		sets := tactics(g)
+		var hangs CellSet
+		for _, mv := range g.GenerateMoves() {
+			next, res, _ := Play(g, mv)
+			win := res.Over() && res.Winner() == g.CurrentPlayer
+			loss := res.Over() && res.Winner() == g.CurrentPlayer.Opponent()
+			hang := !res.Over() && canWin(next)
+			if win != tac.Wins.Contains(mv) || loss != tac.Losses.Contains(mv) || hang != tac.Hangs.Contains(mv) {
+				t.Fatalf("move %v: win %v loss %v hang %v, tactics %+v\n%s", mv, win, loss, hang, tac, g.Board)
+			}
+			if draw := res.Over() && res.Winner() == 0; draw != (sets.draws&(1<<(mv.Row*4+mv.Col)) != 0) {
+				t.Fatalf("move %v: draw %v, draws %016b\n%s", mv, draw, sets.draws, g.Board)
+			}
+			if hang {
+				hangs |= 1 << (mv.Row*4 + mv.Col)
+			}
+			if block := hangs != 0 && !res.Over() && !hang; block && !tac.Blocks.Contains(mv) {
+				t.Fatalf("move %v should block, tactics %+v\n%s", mv, tac, g.Board)
+			}
+		}
+		if hangs == 0 && tac.Blocks != 0 {
+			t.Fatalf("blocks %v without threats\n%s", tac.Blocks, g.Board)
+		}
+		for i, s := range []CellSet{tac.Wins, tac.Losses, tac.Hangs, tac.Blocks} {
+			if s != 0 {
+				seen[i]++
+			}
+		}
+	}
+	for i, c := range seen {
+		if c == 0 {
+			t.Fatalf("no positions with a non-empty set %d", i)
+		}
+	}
+}
+
+func TestCellSet(t *testing.T) {
+	s := CellSet(1<<1 | 1<<14)
+	if s.Len() != 2 || !s.Contains(Move{0, 1}) || !s.Contains(Move{3, 2}) || s.Contains(Move{0, 0}) {
+		t.Fatalf("CellSet %016b", uint16(s))
+	}
+	if got := s.String(); got != "(0,1) (3,2)" {
+		t.Fatalf("String() = %q", got)
+	}
+	if CellSet(0).String() != "-" || len(CellSet(0).Moves()) != 0 {
+		t.Fatal("empty CellSet")
+	}
+}
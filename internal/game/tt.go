@@ -0,0 +1,87 @@
+// File game/tt.go
+package game
+
+/* ---------- 置换表 ---------- */
+
+// Bound 表示置换表中分数的性质。
+type Bound uint8
+
+const (
+	BoundNone  Bound = iota // 空条目
+	BoundExact              // 精确值
+	BoundLower              // 下界（发生 β 剪枝）
+	BoundUpper              // 上界（所有着法都未超过 α）
+)
+
+// noMove 表示条目中没有记录最佳着法。
+const noMove int8 = -1
+
+// ttEntry 为置换表中的一个条目，共 16 字节。
+type ttEntry struct {
+	key   uint64
+	score int32
+	depth int8
+	move  int8 // 最佳着法的格子下标 r*4+c，noMove 表示无
+	bound Bound
+}
+
+// TransTable 是固定大小的置换表，以 Zobrist 键的低位作为下标。
+// 冲突时深度不低于原条目或键不同的新结果会覆盖旧条目。
+type TransTable struct {
+	entries []ttEntry
+	mask    uint64
+}
+
+// NewTransTable 创建一个含 2^bits 个条目的置换表。
+func NewTransTable(bits int) *TransTable {
+	if bits < 1 {
+		bits = 1
+	}
+	return &TransTable{
+		entries: make([]ttEntry, 1<<bits),
+		mask:    1<<bits - 1,
+	}
+}
+
+// Clear 清空所有条目。
+func (t *TransTable) Clear() {
+	clear(t.entries)
+}
+
+// probe 查找键为 key 的条目。
+func (t *TransTable) probe(key uint64) (ttEntry, bool) {
+	e := t.entries[key&t.mask]
+	return e, e.bound != BoundNone && e.key == key
+}
+
+// store 写入一条搜索结果。
+func (t *TransTable) store(key uint64, depth, score int, bound Bound, move int8) {
+	e := &t.entries[key&t.mask]
+	if e.bound != BoundNone && e.key == key && int(e.depth) > depth {
+		return // 同一局面已有更深的结果
+	}
+	*e = ttEntry{key: key, score: int32(score), depth: int8(depth), move: move, bound: bound}
+}
+
+// scoreToTT 把距根结点的胜负分转换为距当前结点的胜负分后再存表，
+// 使同一局面在不同 ply 处命中时仍能得到正确的胜负步数。
+func scoreToTT(score, ply int) int {
+	switch {
+	case score >= mateBound:
+		return score + ply
+	case score <= -mateBound:
+		return score - ply
+	}
+	return score
+}
+
+// scoreFromTT 是 scoreToTT 的逆变换。
+func scoreFromTT(score, ply int) int {
+	switch {
+	case score >= mateBound:
+		return score - ply
+	case score <= -mateBound:
+		return score + ply
+	}
+	return score
+}
@@ -0,0 +1,138 @@
+// File game/zobrist.go
+package game
+
+import (
+	"math/bits"
+
+	"trackLogicChess/internal/player"
+)
+
+// Zobrist 键的构造：
+//
+// 每走一步两圈都会旋转，若按棋盘坐标记录棋子，每次旋转都要重算整圈。
+// 因此这里按“圈内坐标”记录：第 p 步（此前已旋转 p 次）落在格子 i 的棋子，
+// 记为槽位 R^{-p}(i)（R 为一次外圈 + 内圈旋转）。此后棋子随圈旋转，
+// 槽位保持不变，所以：
+//   - 落子：异或 zobPiece[颜色][槽位]
+//   - 旋转：异或掉 zobRot[p%12]，再异或上 zobRot[(p+1)%12]
+//   - 换手：异或 zobSide
+//
+// 同一棋盘上的子数即已旋转次数，因此棋盘相同的局面槽位也相同，不会漏掉置换。
+
+// rotPeriod 为外圈（12 格）与内圈（4 格）同时旋转的周期。
+const rotPeriod = 12
+
+var (
+	zobPiece [3][16]uint64 // zobPiece[颜色][槽位]，下标 0（Empty）不用
+	zobRot   [rotPeriod]uint64
+	zobSide  uint64 // 轮到白方走时异或
+
+	// slotOf[dirOuter][dirInner][p][i]：已旋转 p 次时，格子 i 对应的槽位 R^{-p}(i)
+	slotOf [2][2][rotPeriod][16]uint8
+)
+
+func init() {
+	// splitmix64：固定种子，保证每次运行键值一致
+	seed := uint64(0x9E3779B97F4A7C15)
+	next := func() uint64 {
+		seed += 0x9E3779B97F4A7C15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+	for _, col := range []player.Color{player.Black, player.White} {
+		for i := range zobPiece[col] {
+			zobPiece[col][i] = next()
+		}
+	}
+	for i := range zobRot {
+		zobRot[i] = next()
+	}
+	zobSide = next()
+
+	for _, do := range []Direction{Clockwise, CounterClockwise} {
+		for _, di := range []Direction{Clockwise, CounterClockwise} {
+			// fwd[i]：旋转一次后格子 i 的棋子所在的格子
+			fwd := identityPerm()
+			ringShift(&fwd, outerRing[:], do)
+			ringShift(&fwd, innerRing[:], di)
+
+			// pos[s]：槽位 s 上的棋子在旋转 p 次后所在的格子
+			pos := identityPerm()
+			for p := 0; p < rotPeriod; p++ {
+				for s, cell := range pos {
+					slotOf[do][di][p][cell] = uint8(s)
+				}
+				for s := range pos {
+					pos[s] = fwd[pos[s]]
+				}
+			}
+		}
+	}
+}
+
+// Key 返回当前局面的 Zobrist 键。
+func (g *GameState) Key() uint64 {
+	return g.key
+}
+
+// Rehash 从头计算 Zobrist 键。直接修改 Board 或 CurrentPlayer 之后需调用。
+func (g *GameState) Rehash() {
+	black, white := g.Board.Masks()
+	key := zobristKey(black, white, g.DirOuter, g.DirInner)
+	if g.CurrentPlayer == player.White {
+		key ^= zobSide
+	}
+	g.key = key
+}
+
+// zobristKey 返回棋盘部分（不含走棋方）的 Zobrist 键。
+func zobristKey(black, white uint16, dirOuter, dirInner Direction) uint64 {
+	p := bits.OnesCount16(black|white) % rotPeriod
+	slots := &slotOf[dirOuter][dirInner][p]
+
+	key := zobRot[p]
+	for m := black; m != 0; m &= m - 1 {
+		key ^= zobPiece[player.Black][slots[bits.TrailingZeros16(m)]]
+	}
+	for m := white; m != 0; m &= m - 1 {
+		key ^= zobPiece[player.White][slots[bits.TrailingZeros16(m)]]
+	}
+	return key
+}
+
+// place 在 (r,c) 放置当前玩家的棋子，并增量更新键。
+func (g *GameState) place(r, c int) {
+	p := bits.OnesCount16(g.Board.occupied()) % rotPeriod
+	g.Board.Set(r, c, g.CurrentPlayer)
+	g.key ^= zobPiece[g.CurrentPlayer][slotOf[g.DirOuter][g.DirInner][p][r*4+c]]
+}
+
+// rotate 按固定方向旋转两圈，并增量更新键。须在 place 之后调用。
+func (g *GameState) rotate() {
+	p := bits.OnesCount16(g.Board.occupied()) % rotPeriod // 落子后的子数 = 旋转后的次数
+	Rotate(g.Board, g.DirOuter, g.DirInner)
+	g.key ^= zobRot[(p+rotPeriod-1)%rotPeriod] ^ zobRot[p]
+}
+
+// switchPlayer 切换走棋方，并增量更新键。
+func (g *GameState) switchPlayer() {
+	g.CurrentPlayer = g.CurrentPlayer.Opponent()
+	g.key ^= zobSide
+}
+
+// unrotate 撤销一次 rotate：按相反方向旋转两圈，并增量更新键。
+func (g *GameState) unrotate() {
+	p := bits.OnesCount16(g.Board.occupied()) % rotPeriod
+	Rotate(g.Board, g.DirOuter.Reverse(), g.DirInner.Reverse())
+	g.key ^= zobRot[p] ^ zobRot[(p+rotPeriod-1)%rotPeriod]
+}
+
+// unplace 撤销一次 place：移走 (r,c) 上的棋子，并增量更新键。须在 unrotate 之后调用。
+func (g *GameState) unplace(r, c int) {
+	col := g.Board.Cell(r, c)
+	g.Board.Set(r, c, player.Empty)
+	p := bits.OnesCount16(g.Board.occupied()) % rotPeriod
+	g.key ^= zobPiece[col][slotOf[g.DirOuter][g.DirInner][p][r*4+c]]
+}
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestKeyIncremental 随机对局中，增量维护的键应始终等于从头计算的键。
+func TestKeyIncremental(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		g := NewGame(Direction(n%2), Direction(n/2%2))
+		for !g.IsGameOver() {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			if err := g.ApplyMove(mv.Row, mv.Col); err != nil {
+				t.Fatal(err)
+			}
+			want := g.Clone()
+			want.Rehash()
+			if g.Key() != want.Key() {
+				t.Fatalf("incremental key %x, recomputed %x\n%s", g.Key(), want.Key(), g.Board)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// File match/elo.go
+package match
+
+import (
+	"fmt"
+	"math"
+)
+
+// Score 为一方（A）对另一方（B）的累计战绩。
+type Score struct {
+	Wins, Draws, Losses int
+}
+
+// Games 返回已计入的对局数。
+func (s Score) Games() int {
+	return s.Wins + s.Draws + s.Losses
+}
+
+// Add 计入一局结果：1 为胜，0.5 为和，0 为负。
+func (s *Score) Add(points float64) {
+	switch points {
+	case 1:
+		s.Wins++
+	case 0:
+		s.Losses++
+	default:
+		s.Draws++
+	}
+}
+
+// Mean 返回平均得分（胜 1、和 0.5、负 0），没有对局时为 0.5。
+func (s Score) Mean() float64 {
+	n := s.Games()
+	if n == 0 {
+		return 0.5
+	}
+	return (float64(s.Wins) + 0.5*float64(s.Draws)) / float64(n)
+}
+
+// stats 返回用于估计的平均得分与单局得分方差。
+// 所有对局结果相同（全胜、全和或全负）时方差为 0，Elo 与对数似然比都无从估计，
+// 这时各加半局胜与半局负（合计相当于一局和棋）再计算。
+func (s Score) stats() (mean, variance float64) {
+	w, d, l := float64(s.Wins), float64(s.Draws), float64(s.Losses)
+	if n := s.Games(); s.Wins == n || s.Draws == n || s.Losses == n {
+		w, l = w+0.5, l+0.5
+	}
+	n := w + d + l
+	mean = (w + 0.5*d) / n
+	variance = (w*(1-mean)*(1-mean) + d*(0.5-mean)*(0.5-mean) + l*mean*mean) / n
+	return mean, variance
+}
+
+// Elo 返回 A 相对 B 的 Elo 差及其 95% 置信区间的半宽。
+// 全胜或全负时按 stats 的修正估计，差值有限；区间一端超出得分范围时半宽为 +Inf。
+func (s Score) Elo() (diff, margin float64) {
+	n := s.Games()
+	if n == 0 {
+		return 0, math.Inf(1)
+	}
+	m, v := s.stats()
+	se := math.Sqrt(v / float64(n))
+	lo, hi := eloFromScore(m-1.96*se), eloFromScore(m+1.96*se)
+	return eloFromScore(m), (hi - lo) / 2
+}
+
+func (s Score) String() string {
+	return fmt.Sprintf("+%d =%d -%d", s.Wins, s.Draws, s.Losses)
+}
+
+// eloFromScore 把平均得分换算为 Elo 差（logistic 模型）
+func eloFromScore(m float64) float64 {
+	switch {
+	case m <= 0:
+		return math.Inf(-1)
+	case m >= 1:
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/m-1)
+}
+
+// scoreFromElo 为 eloFromScore 的反函数
+func scoreFromElo(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+/* ---------- SPRT ---------- */
+
+// Decision 为序贯检验的当前结论。
+type Decision int
+
+const (
+	Continue Decision = iota // 证据不足，继续对局
+	AcceptH0                 // 接受 H0：Elo 差不超过 Elo0
+	AcceptH1                 // 接受 H1：Elo 差至少为 Elo1
+)
+
+func (d Decision) String() string {
+	switch d {
+	case AcceptH0:
+		return "H0"
+	case AcceptH1:
+		return "H1"
+	}
+	return "continue"
+}
+
+// SPRT 为检验 H0: elo = Elo0 对 H1: elo = Elo1 的序贯概率比检验，
+// Alpha 与 Beta 分别为第一类与第二类错误率。
+type SPRT struct {
+	Elo0, Elo1  float64
+	Alpha, Beta float64
+}
+
+// Bounds 返回对数似然比的下界与上界：低于下界接受 H0，高于上界接受 H1。
+func (t SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// LLR 返回战绩 s 下的对数似然比（广义 SPRT 的正态近似）。
+// 所有结果相同时按 stats 的修正计算，因此连胜也能接受 H1；没有对局时返回 0。
+func (t SPRT) LLR(s Score) float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+	m, v := s.stats()
+	s0, s1 := scoreFromElo(t.Elo0), scoreFromElo(t.Elo1)
+	return (s1 - s0) * (2*m - s0 - s1) * float64(s.Games()) / (2 * v)
+}
+
+// Test 返回战绩 s 下的对数似然比与结论。
+func (t SPRT) Test(s Score) (llr float64, d Decision) {
+	llr = t.LLR(s)
+	lower, upper := t.Bounds()
+	switch {
+	case llr <= lower:
+		return llr, AcceptH0
+	case llr >= upper:
+		return llr, AcceptH1
+	}
+	return llr, Continue
+}
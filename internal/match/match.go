@@ -0,0 +1,234 @@
+// File match/match.go
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/record"
+)
+
+// 对局安排：
+//   - 对局两两成对，同一对使用相同的开局，A 在前一局执黑、后一局执白；
+//   - 随机开局时，各对依次轮换四种旋转配置（外圈 × 内圈）；
+//   - 指定开局局面时按顺序循环使用，旋转配置以局面为准。
+
+// Engine 为参赛的一方。New 为每局创建一个新的 Player，rng 由对局编号确定，
+// 因而同一 Seed 下的比赛可以复现；Player 实现 io.Closer 时在对局结束后关闭。
+type Engine struct {
+	Name string
+	New  func(rng *rand.Rand) game.Player
+}
+
+// Config 描述一场 A 对 B 的比赛。
+type Config struct {
+	A, B        Engine
+	Games       int               // 对局数，奇数时向上取偶
+	Concurrency int               // 同时进行的对局数，小于 1 时为 1
+	Openings    []*game.GameState // 开局局面，为空时随机开局
+	RandomPlies int               // 随机开局的步数，0 … MaxRandomPlies
+	Seed        int64
+	SPRT        *SPRT // 非 nil 时在得出结论后提前结束
+}
+
+// Game 为一局的结果。
+type Game struct {
+	Index    int
+	AIsBlack bool
+	Record   *record.Record
+}
+
+// PointsA 返回 A 在本局的得分：胜 1、和 0.5、负 0。
+func (g Game) PointsA() float64 {
+	switch g.Record.Result {
+	case game.Draw:
+		return 0.5
+	case game.BlackWins:
+		if g.AIsBlack {
+			return 1
+		}
+	case game.WhiteWins:
+		if !g.AIsBlack {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Summary 为比赛的汇总。
+type Summary struct {
+	Score    Score    // A 的战绩
+	LLR      float64  // 设置了 SPRT 时的对数似然比
+	Decision Decision // 设置了 SPRT 时的结论
+}
+
+// Rotations 为四种旋转配置（外圈、内圈），随机开局按此顺序轮换。
+var Rotations = [4][2]game.Direction{
+	{game.Clockwise, game.Clockwise},
+	{game.Clockwise, game.CounterClockwise},
+	{game.CounterClockwise, game.Clockwise},
+	{game.CounterClockwise, game.CounterClockwise},
+}
+
+// Run 进行比赛，每完成一局（按完成顺序）调用一次 onGame（可为 nil），传入该局与当前汇总。
+// SPRT 得出结论或 ctx 取消时，尚未完成的对局被放弃；ctx 取消时返回已完成部分的汇总与 ctx.Err()。
+// 随机开局的步数超出范围时不进行任何对局，返回 ErrRandomPlies。
+func Run(ctx context.Context, cfg Config, onGame func(Game, Summary)) (Summary, error) {
+	if len(cfg.Openings) == 0 && (cfg.RandomPlies < 0 || cfg.RandomPlies > MaxRandomPlies) {
+		return Summary{}, ErrRandomPlies
+	}
+	games := cfg.Games + cfg.Games%2
+	workers := max(cfg.Concurrency, 1)
+	parent := ctx
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		sum      Summary
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				g, err := playGame(ctx, cfg, i)
+				mu.Lock()
+				switch {
+				case ctx.Err() != nil:
+					// 比赛已结束或被取消：放弃本局
+				case err != nil:
+					if firstErr == nil {
+						firstErr = err
+					}
+					cancel()
+				default:
+					sum.Score.Add(g.PointsA())
+					if cfg.SPRT != nil {
+						sum.LLR, sum.Decision = cfg.SPRT.Test(sum.Score)
+						if sum.Decision != Continue {
+							cancel()
+						}
+					}
+					if onGame != nil {
+						onGame(g, sum)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := 0; i < games; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return sum, firstErr
+	}
+	if sum.Decision == Continue {
+		return sum, parent.Err()
+	}
+	return sum, nil
+}
+
+const (
+	// MaxRandomPlies 为随机开局步数的上限：走满 16 步时棋盘已满，对局必然已经结束。
+	MaxRandomPlies = 15
+	// maxOpeningTries 为 RandomOpening 重新开始的次数上限
+	maxOpeningTries = 1000
+)
+
+// ErrRandomPlies 表示随机开局的步数超出 0 … MaxRandomPlies。
+var ErrRandomPlies = fmt.Errorf("random opening plies must be between 0 and %d", MaxRandomPlies)
+
+// RandomOpening 从 (dirOuter, dirInner) 的空棋盘随机走 plies 步，返回未结束的局面。
+// 中途分出胜负时重新开始，连续 maxOpeningTries 次都未成功时返回错误。
+func RandomOpening(dirOuter, dirInner game.Direction, plies int, rng *rand.Rand) (*game.GameState, error) {
+	if plies < 0 || plies > MaxRandomPlies {
+		return nil, ErrRandomPlies
+	}
+	for try := 0; try < maxOpeningTries; try++ {
+		g := game.NewGame(dirOuter, dirInner)
+		for n := 0; n < plies && !g.IsGameOver(); n++ {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if !g.IsGameOver() {
+			return g, nil
+		}
+	}
+	return nil, errors.New("no unfinished random opening found")
+}
+
+// opening 返回第 i 局的开局局面
+func (cfg *Config) opening(i int) (*game.GameState, error) {
+	pair := i / 2
+	if len(cfg.Openings) > 0 {
+		return cfg.Openings[pair%len(cfg.Openings)].Clone(), nil
+	}
+	rot := Rotations[pair%len(Rotations)]
+	rng := rand.New(rand.NewSource(cfg.Seed ^ int64(pair)<<20))
+	return RandomOpening(rot[0], rot[1], cfg.RandomPlies, rng)
+}
+
+// playGame 进行第 i 局
+func playGame(ctx context.Context, cfg Config, i int) (Game, error) {
+	res := Game{Index: i, AIsBlack: i%2 == 0}
+	a, b := cfg.A, cfg.B
+	seedA, seedB := cfg.Seed+2*int64(i), cfg.Seed+2*int64(i)+1
+	if !res.AIsBlack {
+		a, b = b, a
+		seedA, seedB = seedB, seedA
+	}
+	start, err := cfg.opening(i)
+	if err != nil {
+		return res, fmt.Errorf("match: game %d: %w", i+1, err)
+	}
+	rec, err := PlayGame(ctx, start, a, b, seedA, seedB)
+	res.Record = rec
+	if err != nil {
+		return res, fmt.Errorf("match: game %d: %w", i+1, err)
+	}
+	return res, nil
+}
+
+// PlayGame 让 black 与 white 从局面 start 开始下完一局，返回对局记录。
+// 双方的 Player 分别以 blackSeed、whiteSeed 为随机种子新建，对局结束后关闭。
+// start 会被修改；ctx 取消时返回已走部分的记录与错误。
+func PlayGame(ctx context.Context, start *game.GameState, black, white Engine, blackSeed, whiteSeed int64) (*record.Record, error) {
+	pb := black.New(rand.New(rand.NewSource(blackSeed)))
+	pw := white.New(rand.New(rand.NewSource(whiteSeed)))
+	defer closePlayer(pb)
+	defer closePlayer(pw)
+
+	rec := record.New(start, black.Name, white.Name)
+	d := game.NewDriver(start, pb, pw)
+	d.OnMove = func(mv game.Move, g *game.GameState) {
+		rec.Add(mv, g)
+	}
+	_, err := d.Run(ctx)
+	return rec, err
+}
+
+// closePlayer 关闭实现了 io.Closer 的 Player
+func closePlayer(p game.Player) {
+	if c, ok := p.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,199 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"trackLogicChess/internal/game"
+)
+
+func randomEngine() Engine {
+	return Engine{Name: "random", New: func(rng *rand.Rand) game.Player {
+		return game.PlayerFunc(func(ctx context.Context, g *game.GameState) (game.Move, error) {
+			moves := g.GenerateMoves()
+			return moves[rng.Intn(len(moves))], nil
+		})
+	}}
+}
+
+func searchEngine(depth int) Engine {
+	return Engine{Name: "alphabeta", New: func(rng *rand.Rand) game.Player {
+		return &game.EnginePlayer{
+			Searcher: game.NewSearcherWith(game.Options{TTBits: 16, Rand: rng}),
+			Limits:   game.Limits{Depth: depth},
+		}
+	}}
+}
+
+func TestElo(t *testing.T) {
+	if d, _ := (Score{Wins: 50, Losses: 50}).Elo(); d != 0 {
+		t.Errorf("even score: elo %v", d)
+	}
+	d, m := Score{Wins: 60, Losses: 40}.Elo()
+	if math.Abs(d-70.4) > 0.1 || m <= 0 || m > 100 {
+		t.Errorf("60/40: elo %v ± %v", d, m)
+	}
+	// 全胜、全负与全和按修正后的战绩估计：差值有限，半宽不是 NaN
+	for _, c := range []struct {
+		s    Score
+		sign float64
+	}{{Score{Wins: 3}, 1}, {Score{Wins: 200}, 1}, {Score{Losses: 20}, -1}, {Score{Draws: 20}, 0}} {
+		d, m := c.s.Elo()
+		if math.IsInf(d, 0) || math.IsNaN(d) || math.IsNaN(m) || m <= 0 || d*c.sign < 0 || c.sign == 0 && d != 0 {
+			t.Errorf("%v: elo %v ± %v", c.s, d, m)
+		}
+	}
+	d3, _ := Score{Wins: 3}.Elo()
+	d200, _ := Score{Wins: 200}.Elo()
+	if d3 >= d200 {
+		t.Errorf("3-0 sweep rated %v, 200-0 sweep %v", d3, d200)
+	}
+	if _, m2 := (Score{Wins: 600, Losses: 400}).Elo(); m2 >= m {
+		t.Errorf("margin did not shrink with more games: %v >= %v", m2, m)
+	}
+}
+
+func TestSPRT(t *testing.T) {
+	test := SPRT{Elo0: 0, Elo1: 20, Alpha: 0.05, Beta: 0.05}
+	lower, upper := test.Bounds()
+	if math.Abs(lower+2.944) > 0.001 || math.Abs(upper-2.944) > 0.001 {
+		t.Fatalf("bounds %v %v", lower, upper)
+	}
+	cases := []struct {
+		s    Score
+		want Decision
+	}{
+		{Score{Wins: 5, Draws: 2, Losses: 4}, Continue},
+		{Score{Wins: 700, Draws: 100, Losses: 500}, AcceptH1},
+		{Score{Wins: 500, Draws: 100, Losses: 600}, AcceptH0},
+		// 结果全相同时也能得出结论
+		{Score{Wins: 3}, Continue},
+		{Score{Wins: 12}, AcceptH1},
+		{Score{Losses: 12}, AcceptH0},
+		{Score{Draws: 60}, AcceptH0},
+	}
+	for _, c := range cases {
+		if llr, d := test.Test(c.s); d != c.want {
+			t.Errorf("%v: llr %.2f decision %v, want %v", c.s, llr, d, c.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	cfg := Config{
+		A:           searchEngine(4),
+		B:           randomEngine(),
+		Games:       8,
+		Concurrency: 3,
+		RandomPlies: 2,
+		Seed:        7,
+	}
+	var played []Game
+	sum, err := Run(context.Background(), cfg, func(g Game, s Summary) {
+		played = append(played, g)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Score.Games() != 8 || len(played) != 8 {
+		t.Fatalf("played %d games (%v)", len(played), sum.Score)
+	}
+	if sum.Score.Wins < 6 {
+		t.Errorf("search lost too often to random play: %v", sum.Score)
+	}
+	rots := map[[2]game.Direction]bool{}
+	for _, g := range played {
+		if g.AIsBlack != (g.Index%2 == 0) {
+			t.Errorf("game %d: A is black = %v", g.Index, g.AIsBlack)
+		}
+		if _, err := g.Record.Replay(); err != nil {
+			t.Errorf("game %d: %v", g.Index, err)
+		}
+		if len(g.Record.Plies) == 0 || !g.Record.Result.Over() {
+			t.Errorf("game %d: unfinished record", g.Index)
+		}
+		rots[[2]game.Direction{g.Record.DirOuter, g.Record.DirInner}] = true
+	}
+	if len(rots) != 4 {
+		t.Errorf("played %d rotation configurations, want 4", len(rots))
+	}
+}
+
+func TestRunReproducible(t *testing.T) {
+	cfg := Config{A: searchEngine(3), B: searchEngine(2), Games: 4, RandomPlies: 3, Seed: 11}
+	run := func() []string {
+		var moves []string
+		_, err := Run(context.Background(), cfg, func(g Game, s Summary) {
+			for _, p := range g.Record.Plies {
+				moves = append(moves, p.Board)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return moves
+	}
+	a, b := run(), run()
+	if len(a) != len(b) {
+		t.Fatalf("different lengths %d %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("ply %d differs: %s vs %s", i, a[i], b[i])
+		}
+	}
+}
+
+func TestRunSPRTStops(t *testing.T) {
+	cfg := Config{
+		A:           searchEngine(4),
+		B:           randomEngine(),
+		Games:       400,
+		Concurrency: 2,
+		RandomPlies: 1,
+		Seed:        3,
+		SPRT:        &SPRT{Elo0: 0, Elo1: 100, Alpha: 0.05, Beta: 0.05},
+	}
+	sum, err := Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Decision != AcceptH1 || sum.Score.Games() >= 400 {
+		t.Errorf("decision %v after %d games (llr %.2f)", sum.Decision, sum.Score.Games(), sum.LLR)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg := Config{A: randomEngine(), B: randomEngine(), Games: 10}
+	if _, err := Run(ctx, cfg, nil); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+// TestRandomOpening 随机开局不会走到终局；步数超出范围时 Run 与 RandomOpening 都返回 ErrRandomPlies。
+func TestRandomOpening(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for plies := 0; plies <= MaxRandomPlies; plies++ {
+		g, err := RandomOpening(game.Clockwise, game.CounterClockwise, plies, rng)
+		if err != nil {
+			t.Fatalf("%d plies: %v", plies, err)
+		}
+		if g.IsGameOver() || len(g.GenerateMoves()) != 16-plies {
+			t.Fatalf("%d plies: %s", plies, game.FormatPosition(g))
+		}
+	}
+	for _, plies := range []int{-1, 16, 20} {
+		if _, err := RandomOpening(game.Clockwise, game.Clockwise, plies, rng); !errors.Is(err, ErrRandomPlies) {
+			t.Fatalf("%d plies: %v", plies, err)
+		}
+		cfg := Config{A: randomEngine(), B: randomEngine(), Games: 2, RandomPlies: plies}
+		if _, err := Run(context.Background(), cfg, nil); !errors.Is(err, ErrRandomPlies) {
+			t.Fatalf("Run with %d plies: %v", plies, err)
+		}
+	}
+}
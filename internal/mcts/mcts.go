@@ -0,0 +1,240 @@
+// File mcts/mcts.go
+package mcts
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/player"
+)
+
+// 蒙特卡洛树搜索（UCT）：
+//   - 选择：从根结点出发，在已完全展开的结点中取 UCT 值最大的子结点；
+//   - 展开：为遇到的第一个尚有未试着法的结点新增一个子结点；
+//   - 模拟：按 Policy 从新结点走到终局；
+//   - 回传：沿路径把结果累加到各结点，胜计 1、和计 0.5、负计 0。
+
+const (
+	DefaultExploration = math.Sqrt2 // UCT 探索常数的默认值
+	DefaultIterations  = 20000      // 未指定任何预算时的迭代次数
+)
+
+// Policy 为模拟阶段的走子策略：返回 g（未结束）中的一步合法着法。
+type Policy func(g *game.GameState, rng *rand.Rand) game.Move
+
+// RandomPolicy 在所有空格中均匀随机选择。
+func RandomPolicy(g *game.GameState, rng *rand.Rand) game.Move {
+	moves := g.GenerateMoves()
+	return moves[rng.Intn(len(moves))]
+}
+
+// GreedyPolicy 能一步取胜时取胜，否则随机选择。
+func GreedyPolicy(g *game.GameState, rng *rand.Rand) game.Move {
+	moves := g.GenerateMoves()
+	for _, mv := range moves {
+		if _, res, _ := game.Play(g, mv); res.Winner() == g.CurrentPlayer {
+			return mv
+		}
+	}
+	return moves[rng.Intn(len(moves))]
+}
+
+// Config 为引擎参数，零值字段使用默认值。
+type Config struct {
+	Exploration float64    // UCT 探索常数 c，0 表示 DefaultExploration
+	Playout     Policy     // 模拟策略，nil 表示 RandomPolicy
+	Rand        *rand.Rand // 随机源，nil 表示以当前时间为种子
+}
+
+// Limits 限定一次搜索的预算，零值字段表示不限制；两者都为零时迭代 DefaultIterations 次。
+type Limits struct {
+	Iterations int           // 迭代次数
+	MoveTime   time.Duration // 时间预算
+}
+
+// MoveStat 为根结点某一候选着法的统计。
+type MoveStat struct {
+	Move    game.Move
+	Visits  int
+	WinRate float64 // 走棋方视角的平均得分（和棋计 0.5），0–1
+}
+
+// Result 为一次搜索的结果。
+type Result struct {
+	Move       game.Move  // 访问次数最多的着法；无合法着法时为 (-1,-1)
+	Iterations int        // 本次搜索新做的迭代次数
+	Reused     int        // 从上一次搜索继承的根结点访问次数
+	Moves      []MoveStat // 全部已展开的候选着法，按访问次数从多到少排列
+}
+
+// node 为搜索树中的一个结点。
+type node struct {
+	state    *game.GameState
+	move     game.Move    // 从父结点走到本结点的着法
+	mover    player.Color // 走出 move 的一方
+	parent   *node
+	children []*node
+	untried  []game.Move
+	visits   int
+	score    float64 // mover 视角的累计得分
+}
+
+// newNode 为局面 g 创建结点，未试着法的顺序随机打乱。
+func newNode(g *game.GameState, mv game.Move, mover player.Color, parent *node, rng *rand.Rand) *node {
+	n := &node{state: g, move: mv, mover: mover, parent: parent, untried: g.GenerateMoves()}
+	rng.Shuffle(len(n.untried), func(i, j int) { n.untried[i], n.untried[j] = n.untried[j], n.untried[i] })
+	return n
+}
+
+// uct 返回子结点 c 的 UCT 值，logParent 为父结点访问次数的自然对数。
+func (c *node) uct(logParent, exploration float64) float64 {
+	v := float64(c.visits)
+	return c.score/v + exploration*math.Sqrt(logParent/v)
+}
+
+// Engine 是可复用的 MCTS 引擎，在连续的着法之间保留搜索树。
+// 同一个 Engine 不能被多个 goroutine 同时使用。
+type Engine struct {
+	cfg  Config
+	rng  *rand.Rand
+	root *node
+}
+
+// New 按 cfg 创建引擎。
+func New(cfg Config) *Engine {
+	if cfg.Exploration == 0 {
+		cfg.Exploration = DefaultExploration
+	}
+	if cfg.Playout == nil {
+		cfg.Playout = RandomPolicy
+	}
+	rng := cfg.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &Engine{cfg: cfg, rng: rng}
+}
+
+// Reset 丢弃保留的搜索树。
+func (e *Engine) Reset() {
+	e.root = nil
+}
+
+// Search 对局面 g 做 MCTS，直到用完 lim 中的预算或 ctx 被取消。
+// 若 g 是上一次搜索的根结点或其后一两步的局面，则沿用对应子树的统计。
+func (e *Engine) Search(ctx context.Context, g *game.GameState, lim Limits) Result {
+	start := time.Now()
+	if lim.Iterations <= 0 && lim.MoveTime <= 0 {
+		lim.Iterations = DefaultIterations
+	}
+
+	root := e.find(g)
+	if root == nil {
+		root = newNode(g.Clone(), game.Move{Row: -1, Col: -1}, player.Empty, nil, e.rng)
+	}
+	root.parent = nil
+	e.root = root
+
+	res := Result{Move: game.Move{Row: -1, Col: -1}, Reused: root.visits}
+	if g.IsGameOver() || len(root.children)+len(root.untried) == 0 {
+		return res
+	}
+	for lim.Iterations <= 0 || res.Iterations < lim.Iterations {
+		if res.Iterations&63 == 0 && res.Iterations > 0 {
+			if ctx.Err() != nil || lim.MoveTime > 0 && time.Since(start) >= lim.MoveTime {
+				break
+			}
+		}
+		e.iterate(root)
+		res.Iterations++
+	}
+
+	for _, c := range root.children {
+		res.Moves = append(res.Moves, MoveStat{
+			Move:    c.move,
+			Visits:  c.visits,
+			WinRate: c.score / float64(c.visits),
+		})
+	}
+	sort.SliceStable(res.Moves, func(i, j int) bool { return res.Moves[i].Visits > res.Moves[j].Visits })
+	res.Move = res.Moves[0].Move
+	return res
+}
+
+// find 在保留的树中查找与 g 相同的局面（根结点及其后两层），找不到时返回 nil。
+func (e *Engine) find(g *game.GameState) *node {
+	if e.root == nil {
+		return nil
+	}
+	same := func(n *node) bool {
+		s := n.state
+		return s.Key() == g.Key() && s.DirOuter == g.DirOuter && s.DirInner == g.DirInner &&
+			*s.Board == *g.Board && s.CurrentPlayer == g.CurrentPlayer
+	}
+	if same(e.root) {
+		return e.root
+	}
+	for _, c := range e.root.children {
+		if same(c) {
+			return c
+		}
+		for _, gc := range c.children {
+			if same(gc) {
+				return gc
+			}
+		}
+	}
+	return nil
+}
+
+// iterate 完成一次选择、展开、模拟与回传。
+func (e *Engine) iterate(root *node) {
+	n := root
+	for len(n.untried) == 0 && len(n.children) > 0 {
+		n = e.selectChild(n)
+	}
+	if len(n.untried) > 0 {
+		mv := n.untried[len(n.untried)-1]
+		n.untried = n.untried[:len(n.untried)-1]
+		next, _, _ := game.Play(n.state, mv)
+		c := newNode(next, mv, n.state.CurrentPlayer, n, e.rng)
+		n.children = append(n.children, c)
+		n = c
+	}
+
+	winner := e.simulate(n.state)
+	for ; n != nil; n = n.parent {
+		n.visits++
+		switch winner {
+		case n.mover:
+			n.score++
+		case player.Empty:
+			n.score += 0.5
+		}
+	}
+}
+
+// selectChild 返回 UCT 值最大的子结点。
+func (e *Engine) selectChild(n *node) *node {
+	logParent := math.Log(float64(n.visits))
+	best, bestVal := n.children[0], math.Inf(-1)
+	for _, c := range n.children {
+		if v := c.uct(logParent, e.cfg.Exploration); v > bestVal {
+			best, bestVal = c, v
+		}
+	}
+	return best
+}
+
+// simulate 从 g 按模拟策略走到终局，返回胜者（平局为 player.Empty）。
+func (e *Engine) simulate(g *game.GameState) player.Color {
+	res := g.Result()
+	for !res.Over() {
+		g, res, _ = game.Play(g, e.cfg.Playout(g, e.rng))
+	}
+	return res.Winner()
+}
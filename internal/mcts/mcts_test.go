@@ -0,0 +1,95 @@
+package mcts
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"trackLogicChess/internal/game"
+)
+
+// winInOne 用固定种子的随机对局找一个走棋方能一步取胜、但并非每步都取胜的局面。
+func winInOne(t *testing.T) (*game.GameState, []game.Move) {
+	rng := rand.New(rand.NewSource(5))
+	for n := 0; n < 1000; n++ {
+		g := game.NewGame(game.Clockwise, game.Clockwise)
+		for !g.IsGameOver() {
+			moves := g.GenerateMoves()
+			var wins []game.Move
+			for _, mv := range moves {
+				if _, res, _ := game.Play(g, mv); res.Winner() == g.CurrentPlayer {
+					wins = append(wins, mv)
+				}
+			}
+			if len(wins) > 0 && len(wins) < len(moves)/2 {
+				return g, wins
+			}
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+	t.Fatal("no win-in-one position found")
+	return nil, nil
+}
+
+func TestSearchFindsWin(t *testing.T) {
+	g, wins := winInOne(t)
+	e := New(Config{Rand: rand.New(rand.NewSource(1))})
+	res := e.Search(context.Background(), g, Limits{Iterations: 5000})
+	found := false
+	for _, mv := range wins {
+		found = found || mv == res.Move
+	}
+	if !found {
+		t.Fatalf("MCTS chose %v, winning moves are %v", res.Move, wins)
+	}
+	total := 0
+	for _, st := range res.Moves {
+		total += st.Visits
+		if st.WinRate < 0 || st.WinRate > 1 {
+			t.Errorf("move %v has win rate %v", st.Move, st.WinRate)
+		}
+	}
+	if total != res.Iterations {
+		t.Errorf("root children visited %d times, want %d", total, res.Iterations)
+	}
+}
+
+// TestTreeReuse 走出搜索给出的着法及一步应对后，再次搜索应继承子树的访问次数。
+func TestTreeReuse(t *testing.T) {
+	g := game.NewGame(game.Clockwise, game.CounterClockwise)
+	e := New(Config{Rand: rand.New(rand.NewSource(2))})
+	res := e.Search(context.Background(), g, Limits{Iterations: 3000})
+	if res.Reused != 0 {
+		t.Fatalf("first search reused %d visits", res.Reused)
+	}
+	_ = g.ApplyMove(res.Move.Row, res.Move.Col)
+	reply := g.GenerateMoves()[0]
+	_ = g.ApplyMove(reply.Row, reply.Col)
+
+	res = e.Search(context.Background(), g, Limits{Iterations: 100})
+	if res.Reused == 0 {
+		t.Fatal("second search did not reuse the tree")
+	}
+	if !g.Board.IsEmpty(res.Move.Row, res.Move.Col) {
+		t.Fatalf("illegal move %v", res.Move)
+	}
+}
+
+// TestSeededSearchIsDeterministic 相同种子的两次搜索给出相同的统计。
+func TestSeededSearchIsDeterministic(t *testing.T) {
+	g := game.NewGame(game.CounterClockwise, game.Clockwise)
+	run := func() Result {
+		e := New(Config{Rand: rand.New(rand.NewSource(9)), Playout: GreedyPolicy})
+		return e.Search(context.Background(), g, Limits{Iterations: 500})
+	}
+	a, b := run(), run()
+	if a.Move != b.Move || len(a.Moves) != len(b.Moves) {
+		t.Fatalf("results differ: %v vs %v", a.Move, b.Move)
+	}
+	for i := range a.Moves {
+		if a.Moves[i] != b.Moves[i] {
+			t.Fatalf("stat %d differs: %+v vs %+v", i, a.Moves[i], b.Moves[i])
+		}
+	}
+}
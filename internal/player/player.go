@@ -22,3 +22,14 @@ func (c Color) String() string {
 		return "Empty"
 	}
 }
+
+// Opponent 返回对方的颜色；Empty 的对方仍为 Empty。
+func (c Color) Opponent() Color {
+	switch c {
+	case Black:
+		return White
+	case White:
+		return Black
+	}
+	return Empty
+}
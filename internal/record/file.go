@@ -0,0 +1,47 @@
+// File record/file.go
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Save 把记录写入文件 path：扩展名为 .json 时用 JSON 格式，否则用文本格式。
+func (r *Record) Save(path string) error {
+	var buf bytes.Buffer
+	if isJSON(path) {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	} else if err := r.Write(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Load 读取 Save 写出的记录文件，格式按扩展名判断。不校验着法，需要时调用 Replay。
+func Load(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON(path) {
+		r := &Record{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	return Read(bytes.NewReader(data))
+}
+
+// isJSON 判断 path 是否应使用 JSON 格式。
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -0,0 +1,88 @@
+// File record/json.go
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"trackLogicChess/internal/game"
+)
+
+// jsonRecord 为记录的 JSON 形式，方向与结果都写成与文本格式相同的字符串。
+type jsonRecord struct {
+	Outer    string     `json:"outer"`
+	Inner    string     `json:"inner"`
+	Start    string     `json:"start,omitempty"`
+	Black    string     `json:"black"`
+	White    string     `json:"white"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Result   string     `json:"result"`
+	Moves    []jsonPly  `json:"moves"`
+}
+
+type jsonPly struct {
+	Row   int    `json:"row"`
+	Col   int    `json:"col"`
+	Board string `json:"board,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler。
+func (r *Record) MarshalJSON() ([]byte, error) {
+	jr := jsonRecord{
+		Outer:  r.DirOuter.String(),
+		Inner:  r.DirInner.String(),
+		Start:  r.Start,
+		Black:  r.Black,
+		White:  r.White,
+		Result: ResultToken(r.Result),
+		Moves:  make([]jsonPly, len(r.Plies)),
+	}
+	if !r.Started.IsZero() {
+		jr.Started = &r.Started
+	}
+	if !r.Finished.IsZero() {
+		jr.Finished = &r.Finished
+	}
+	for i, p := range r.Plies {
+		jr.Moves[i] = jsonPly{Row: p.Move.Row, Col: p.Move.Col, Board: p.Board}
+	}
+	return json.Marshal(jr)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler。不校验着法，需要时调用 Replay。
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var jr jsonRecord
+	if err := json.Unmarshal(data, &jr); err != nil {
+		return err
+	}
+	var out Record
+	var err error
+	if out.DirOuter, err = game.ParseDirection(jr.Outer); err != nil {
+		return fmt.Errorf("record: outer: %v", err)
+	}
+	if out.DirInner, err = game.ParseDirection(jr.Inner); err != nil {
+		return fmt.Errorf("record: inner: %v", err)
+	}
+	res, ok := parseResultToken(jr.Result)
+	if !ok {
+		return fmt.Errorf("record: invalid result %q", jr.Result)
+	}
+	out.Result = res
+	out.Start, out.Black, out.White = jr.Start, jr.Black, jr.White
+	if jr.Started != nil {
+		out.Started = *jr.Started
+	}
+	if jr.Finished != nil {
+		out.Finished = *jr.Finished
+	}
+	for _, p := range jr.Moves {
+		if p.Row < 0 || p.Row > 3 || p.Col < 0 || p.Col > 3 {
+			return fmt.Errorf("record: bad move %d,%d", p.Row, p.Col)
+		}
+		out.Plies = append(out.Plies, Ply{Move: game.Move{Row: p.Row, Col: p.Col}, Board: p.Board})
+	}
+	*r = out
+	return nil
+}
@@ -0,0 +1,114 @@
+// File record/record.go
+package record
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"trackLogicChess/internal/game"
+)
+
+// Record 是一局棋的完整记录：旋转配置、起始局面、双方、时间、结果与着法序列。
+// 文本格式见 text.go，JSON 格式见 json.go。
+type Record struct {
+	DirOuter game.Direction
+	DirInner game.Direction
+	Start    string // 起始局面记法；空串表示该旋转配置下的初始局面
+	Black    string // 执黑一方的说明，例如 "human"、"alphabeta depth=6"
+	White    string
+	Started  time.Time // 开局时间，零值表示未记录
+	Finished time.Time // 结束时间，零值表示未记录
+	Result   game.Result
+	Plies    []Ply
+}
+
+// Ply 为记录中的一步。
+type Ply struct {
+	Move  game.Move
+	Board string // 落子并旋转后的棋盘（局面记法的棋盘字段）；空串表示未记录
+}
+
+var (
+	ErrIllegalMove = errors.New("record: illegal move")
+	ErrMismatch    = errors.New("record: recorded board does not match replay")
+	ErrResult      = errors.New("record: recorded result does not match replay")
+)
+
+// New 以局面 g 为起点新建记录，开局时间取当前时间。
+func New(g *game.GameState, black, white string) *Record {
+	r := &Record{
+		DirOuter: g.DirOuter,
+		DirInner: g.DirInner,
+		Black:    black,
+		White:    white,
+		Started:  time.Now(),
+		Result:   g.Result(),
+	}
+	if start := game.StartPosition(g.DirOuter, g.DirInner); game.FormatPosition(g) != start {
+		r.Start = game.FormatPosition(g)
+	}
+	return r
+}
+
+// Add 追加一步着法，after 为走完该步后的局面；对局因此结束时同时记下结果与结束时间。
+func (r *Record) Add(mv game.Move, after *game.GameState) {
+	r.Plies = append(r.Plies, Ply{Move: mv, Board: game.FormatBoard(after.Board)})
+	r.Result = after.Result()
+	if r.Result.Over() {
+		r.Finished = time.Now()
+	}
+}
+
+// Undo 去掉最后一步（悔棋），after 为悔棋后的局面。
+func (r *Record) Undo(after *game.GameState) {
+	if len(r.Plies) == 0 {
+		return
+	}
+	r.Plies = r.Plies[:len(r.Plies)-1]
+	r.Result = after.Result()
+	r.Finished = time.Time{}
+}
+
+// StartState 返回记录的起始局面。
+func (r *Record) StartState() (*game.GameState, error) {
+	if r.Start == "" {
+		return game.NewGame(r.DirOuter, r.DirInner), nil
+	}
+	g, err := game.ParsePosition(r.Start)
+	if err != nil {
+		return nil, err
+	}
+	if g.DirOuter != r.DirOuter || g.DirInner != r.DirInner {
+		return nil, fmt.Errorf("record: start position directions %v/%v differ from record %v/%v",
+			g.DirOuter, g.DirInner, r.DirOuter, r.DirInner)
+	}
+	return g, nil
+}
+
+// Replay 从起始局面依次重走全部着法，返回每一步之后的局面（下标 0 为起始局面，
+// 共 len(Plies)+1 个）。同时校验着法合法、记录的棋盘与重走结果一致，
+// 以及记录的结果与最终局面一致。
+func (r *Record) Replay() ([]*game.GameState, error) {
+	g, err := r.StartState()
+	if err != nil {
+		return nil, err
+	}
+	states := []*game.GameState{g}
+	for i, p := range r.Plies {
+		next, _, err := game.Play(g, p.Move)
+		if err != nil {
+			return states, fmt.Errorf("%w: ply %d %v: %v", ErrIllegalMove, i+1, p.Move, err)
+		}
+		if p.Board != "" && p.Board != game.FormatBoard(next.Board) {
+			return states, fmt.Errorf("%w: ply %d recorded %s, replay gives %s",
+				ErrMismatch, i+1, p.Board, game.FormatBoard(next.Board))
+		}
+		states = append(states, next)
+		g = next
+	}
+	if g.Result() != r.Result {
+		return states, fmt.Errorf("%w: recorded %v, replay gives %v", ErrResult, r.Result, g.Result())
+	}
+	return states, nil
+}
@@ -0,0 +1,164 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"trackLogicChess/internal/game"
+)
+
+// randomRecord 用随机着法从 g 下完一局（或下 maxPlies 步），返回记录。
+func randomRecord(rng *rand.Rand, g *game.GameState, maxPlies int) *Record {
+	r := New(g, "random", "random")
+	r.Started = r.Started.Truncate(time.Second)
+	for i := 0; i < maxPlies && !g.IsGameOver(); i++ {
+		moves := g.GenerateMoves()
+		mv := moves[rng.Intn(len(moves))]
+		_ = g.ApplyMove(mv.Row, mv.Col)
+		r.Add(mv, g)
+	}
+	r.Finished = r.Finished.Truncate(time.Second)
+	return r
+}
+
+// sameRecord 比较两条记录（时间按秒比较）。
+func sameRecord(a, b *Record) bool {
+	if a.DirOuter != b.DirOuter || a.DirInner != b.DirInner || a.Start != b.Start ||
+		a.Black != b.Black || a.White != b.White || a.Result != b.Result ||
+		!a.Started.Equal(b.Started) || !a.Finished.Equal(b.Finished) || len(a.Plies) != len(b.Plies) {
+		return false
+	}
+	for i := range a.Plies {
+		if a.Plies[i] != b.Plies[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// testRecords 返回若干完整对局、未完对局以及从白方走棋的局面开始的对局。
+func testRecords() []*Record {
+	rng := rand.New(rand.NewSource(4))
+	var out []*Record
+	for n := 0; n < 20; n++ {
+		out = append(out, randomRecord(rng, game.NewGame(game.Direction(n%2), game.Direction(n/2%2)), 16))
+	}
+	out = append(out, randomRecord(rng, game.NewGame(game.Clockwise, game.Clockwise), 5))
+	g, _ := game.ParsePosition("4/1b2/4/4 w cw ccw -")
+	out = append(out, randomRecord(rng, g, 16))
+	return out
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for _, r := range testRecords() {
+		var buf bytes.Buffer
+		if err := r.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("Read: %v\n%s", err, buf.String())
+		}
+		if !sameRecord(r, got) {
+			t.Fatalf("text round trip changed the record:\n%s", buf.String())
+		}
+		if _, err := got.Replay(); err != nil {
+			t.Fatalf("Replay: %v", err)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, r := range testRecords() {
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &Record{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if !sameRecord(r, got) {
+			t.Fatalf("JSON round trip changed the record:\n%s", data)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	r := testRecords()[0]
+	dir := t.TempDir()
+	for _, name := range []string{"game.tlc", "game.json"} {
+		path := filepath.Join(dir, name)
+		if err := r.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameRecord(r, got) {
+			t.Fatalf("%s: Save/Load changed the record", name)
+		}
+	}
+}
+
+// TestReplay 重走得到每一步的局面，并能发现被篡改的棋盘、结果与非法着法。
+func TestReplay(t *testing.T) {
+	r := testRecords()[0]
+	states, err := r.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != len(r.Plies)+1 {
+		t.Fatalf("Replay returned %d states for %d plies", len(states), len(r.Plies))
+	}
+	for i, p := range r.Plies {
+		if game.FormatBoard(states[i+1].Board) != p.Board {
+			t.Fatalf("state %d does not match recorded board", i+1)
+		}
+	}
+
+	bad := *r
+	bad.Plies = append([]Ply(nil), r.Plies...)
+	bad.Plies[1].Board = strings.Repeat("4/", 3) + "4"
+	if _, err := bad.Replay(); !errors.Is(err, ErrMismatch) {
+		t.Errorf("tampered board: err = %v", err)
+	}
+
+	bad.Plies = append([]Ply(nil), r.Plies...)
+	for _, mv := range game.NewGame(r.DirOuter, r.DirInner).GenerateMoves() {
+		if !states[1].Board.IsEmpty(mv.Row, mv.Col) {
+			bad.Plies[1].Move = mv // 第一步棋子旋转后所在的格子
+		}
+	}
+	if _, err := bad.Replay(); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("move onto an occupied cell: err = %v", err)
+	}
+
+	bad.Plies = r.Plies
+	bad.Result = game.Ongoing
+	if _, err := bad.Replay(); !errors.Is(err, ErrResult) {
+		t.Errorf("wrong result: err = %v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, s := range []string{
+		"[Outer \"up\"]\n\n*\n",
+		"[Black human]\n\n*\n",
+		"1. 4,0 *\n",
+		"1. 0,0 1-0 0,1\n",
+		"{4/4/4/4} 0,0\n",
+		"[Result \"1-0\"]\n\n1. 0,0 0-1\n",
+	} {
+		if _, err := Read(strings.NewReader(s)); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Read(%q) error = %v, want ErrSyntax", s, err)
+		}
+	}
+}
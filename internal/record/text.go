@@ -0,0 +1,241 @@
+// File record/text.go
+package record
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackLogicChess/internal/game"
+)
+
+// 文本格式仿照国际象棋的 PGN：先是若干 [名称 "值"] 标签行，空一行后是着法部分。
+//
+//	[Black "human"]
+//	[White "alphabeta depth=6"]
+//	[Outer "cw"]
+//	[Inner "ccw"]
+//	[Started "2026-10-17T16:00:00Z"]
+//	[Finished "2026-10-17T16:03:12Z"]
+//	[Result "1-0"]
+//
+//	1. 1,1 {4/1b2/4/4} 0,0 {4/1b2/4/w3}
+//	2. ...
+//	1-0
+//
+// 着法写作 row,col，花括号中是落子并旋转后的棋盘（可省略）。每个回合以“n.”开头，
+// 起始局面轮到白方时第一回合写作“1...”。结果为 1-0（黑胜）、0-1（白胜）、
+// 1/2-1/2（平局）或 *（未结束）。起始局面不是初始局面时以 [Start "记法"] 给出。
+
+var ErrSyntax = errors.New("record: syntax error")
+
+// ResultToken 返回结果的记法：1-0、0-1、1/2-1/2 或 *（未结束），用于棋谱的结果部分。
+func ResultToken(r game.Result) string {
+	switch r {
+	case game.BlackWins:
+		return "1-0"
+	case game.WhiteWins:
+		return "0-1"
+	case game.Draw:
+		return "1/2-1/2"
+	}
+	return "*"
+}
+
+// parseResultToken 是 ResultToken 的逆变换。
+func parseResultToken(s string) (game.Result, bool) {
+	for _, r := range []game.Result{game.Ongoing, game.BlackWins, game.WhiteWins, game.Draw} {
+		if ResultToken(r) == s {
+			return r, true
+		}
+	}
+	return game.Ongoing, false
+}
+
+// Write 以文本格式把记录写入 w。
+func (r *Record) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	tag := func(name, value string) {
+		fmt.Fprintf(bw, "[%s %s]\n", name, strconv.Quote(value))
+	}
+	tag("Black", r.Black)
+	tag("White", r.White)
+	tag("Outer", r.DirOuter.String())
+	tag("Inner", r.DirInner.String())
+	if r.Start != "" {
+		tag("Start", r.Start)
+	}
+	if !r.Started.IsZero() {
+		tag("Started", r.Started.Format(time.RFC3339))
+	}
+	if !r.Finished.IsZero() {
+		tag("Finished", r.Finished.Format(time.RFC3339))
+	}
+	tag("Result", ResultToken(r.Result))
+	bw.WriteString("\n")
+
+	// 起始局面轮到白方时，第一回合只有白方的一步
+	fields := strings.Fields(r.Start)
+	whiteFirst := len(fields) > 1 && fields[1] == "w"
+	turn := 1
+	for i, p := range r.Plies {
+		blackMove := (i%2 == 0) != whiteFirst
+		switch {
+		case blackMove:
+			fmt.Fprintf(bw, "%d. ", turn)
+		case i == 0:
+			fmt.Fprintf(bw, "%d... ", turn)
+		}
+		fmt.Fprintf(bw, "%d,%d", p.Move.Row, p.Move.Col)
+		if p.Board != "" {
+			fmt.Fprintf(bw, " {%s}", p.Board)
+		}
+		if blackMove {
+			bw.WriteString(" ")
+		} else {
+			bw.WriteString("\n")
+			turn++
+		}
+	}
+	if len(r.Plies) > 0 && (len(r.Plies)%2 == 1) != whiteFirst {
+		bw.WriteString("\n")
+	}
+	bw.WriteString(ResultToken(r.Result) + "\n")
+	return bw.Flush()
+}
+
+// Read 从 r 读取一条文本格式的记录。不校验着法，需要时调用 Replay。
+func Read(rd io.Reader) (*Record, error) {
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	r := &Record{}
+	var body strings.Builder
+	resultTag := ""
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			body.WriteString(line + "\n")
+			continue
+		}
+		name, value, err := parseTag(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrSyntax, n+1, err)
+		}
+		if err := r.setTag(name, value); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrSyntax, n+1, err)
+		}
+		if name == "Result" {
+			resultTag = value
+		}
+	}
+
+	resultTok := ""
+	text := body.String()
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t\r\n")
+		if text == "" {
+			break
+		}
+		if text[0] == '{' {
+			end := strings.IndexByte(text, '}')
+			if end < 0 || len(r.Plies) == 0 {
+				return nil, fmt.Errorf("%w: misplaced board comment", ErrSyntax)
+			}
+			r.Plies[len(r.Plies)-1].Board = strings.TrimSpace(text[1:end])
+			text = text[end+1:]
+			continue
+		}
+		end := strings.IndexAny(text, " \t\r\n{")
+		if end < 0 {
+			end = len(text)
+		}
+		tok := text[:end]
+		text = text[end:]
+		switch {
+		case resultTok != "":
+			return nil, fmt.Errorf("%w: %q after the result", ErrSyntax, tok)
+		case strings.HasSuffix(tok, "."):
+			if _, err := strconv.Atoi(strings.TrimRight(tok, ".")); err != nil {
+				return nil, fmt.Errorf("%w: bad move number %q", ErrSyntax, tok)
+			}
+		case strings.Contains(tok, ","):
+			mv, err := parseMove(tok)
+			if err != nil {
+				return nil, err
+			}
+			r.Plies = append(r.Plies, Ply{Move: mv})
+		default:
+			res, ok := parseResultToken(tok)
+			if !ok {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, tok)
+			}
+			resultTok = tok
+			r.Result = res
+		}
+	}
+	if resultTok != "" && resultTag != "" && resultTok != resultTag {
+		return nil, fmt.Errorf("%w: Result tag %s but movetext ends with %s", ErrSyntax, resultTag, resultTok)
+	}
+	return r, nil
+}
+
+// parseTag 解析一行 [名称 "值"]。
+func parseTag(line string) (name, value string, err error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("unterminated tag %q", line)
+	}
+	name, quoted, ok := strings.Cut(line[1:len(line)-1], " ")
+	if !ok {
+		return "", "", fmt.Errorf("tag %q has no value", line)
+	}
+	value, err = strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", fmt.Errorf("tag %s: %v", name, err)
+	}
+	return name, value, nil
+}
+
+// setTag 把一个标签写入记录；未知的标签被忽略。
+func (r *Record) setTag(name, value string) error {
+	var err error
+	switch name {
+	case "Black":
+		r.Black = value
+	case "White":
+		r.White = value
+	case "Outer":
+		r.DirOuter, err = game.ParseDirection(value)
+	case "Inner":
+		r.DirInner, err = game.ParseDirection(value)
+	case "Start":
+		r.Start = value
+	case "Started":
+		r.Started, err = time.Parse(time.RFC3339, value)
+	case "Finished":
+		r.Finished, err = time.Parse(time.RFC3339, value)
+	case "Result":
+		res, ok := parseResultToken(value)
+		if !ok {
+			return fmt.Errorf("invalid result %q", value)
+		}
+		r.Result = res
+	}
+	return err
+}
+
+// parseMove 解析 row,col 形式的着法。
+func parseMove(tok string) (game.Move, error) {
+	rs, cs, _ := strings.Cut(tok, ",")
+	row, err1 := strconv.Atoi(rs)
+	col, err2 := strconv.Atoi(cs)
+	if err1 != nil || err2 != nil || row < 0 || row > 3 || col < 0 || col > 3 {
+		return game.Move{}, fmt.Errorf("%w: bad move %q", ErrSyntax, tok)
+	}
+	return game.Move{Row: row, Col: col}, nil
+}
@@ -0,0 +1,153 @@
+// File solver/index.go
+package solver
+
+import (
+	"math/bits"
+	"sync"
+
+	"trackLogicChess/internal/game"
+)
+
+// 合法局面中黑子数 nb 与白子数 nw 满足 nw == nb 或 nw == nb-1（黑先手）。
+// Index 把这样的局面映射到 [0, NumPositions) 上的唯一整数（最小完美哈希）：
+//
+//	Index = offset[nb][nw] + rank(占用格) * C(nb+nw, nb) + rank(黑子在占用格中的位置)
+//
+// 其中 rank 为组合的 colex 排名。
+
+var (
+	binom  [17][17]int // binom[n][k] = C(n, k)
+	offset [9][9]int   // offset[nb][nw]：该子数组合在索引空间中的起点
+)
+
+// NumPositions 为合法子数局面的总数。
+var NumPositions int
+
+func init() {
+	for n := 0; n <= 16; n++ {
+		binom[n][0] = 1
+		for k := 1; k <= n; k++ {
+			binom[n][k] = binom[n-1][k-1] + binom[n-1][k]
+		}
+	}
+	for nb := 0; nb <= 8; nb++ {
+		for _, nw := range []int{nb - 1, nb} {
+			if nw < 0 {
+				continue
+			}
+			offset[nb][nw] = NumPositions
+			NumPositions += binom[16][nb+nw] * binom[nb+nw][nb]
+		}
+	}
+}
+
+// Index 返回棋盘的完美哈希；子数不合法时 ok 为 false。
+func Index(b *game.Board) (idx int, ok bool) {
+	black, white := b.Masks()
+	nb, nw := bits.OnesCount16(black), bits.OnesCount16(white)
+	if nb > 8 || (nw != nb && nw != nb-1) {
+		return 0, false
+	}
+	occ := black | white
+
+	occRank, blackRank := 0, 0
+	k, kb := 0, 0 // 已遍历的占用格数、黑子数
+	for m := occ; m != 0; m &= m - 1 {
+		p := bits.TrailingZeros16(m)
+		k++
+		occRank += binom[p][k]
+		if black&(1<<p) != 0 {
+			kb++
+			blackRank += binom[k-1][kb]
+		}
+	}
+	return offset[nb][nw] + occRank*binom[nb+nw][nb] + blackRank, true
+}
+
+// 求解表只为规范棋盘（见 game.IsCanonical）保存条目。CanonicalIndex 把规范棋盘按 Index 的顺序
+// 稠密编号：canonBits 的第 i 位标记 Index 为 i 的棋盘是否规范，canonRank[w] 为前 w 个字中的置位总数，
+// 于是编号为 canonRank[i/64] 加上第 i/64 个字中低于第 i%64 位的置位数。
+// 这些数据约 1.9 MB，在第一次用到时构建。
+var (
+	canonOnce    sync.Once
+	canonBits    []uint64
+	canonRank    []uint32
+	numCanonical int
+)
+
+// NumCanonical 返回子数合法的规范棋盘总数（约为 NumPositions 的四分之一）。
+func NumCanonical() int {
+	canonOnce.Do(buildCanonical)
+	return numCanonical
+}
+
+// CanonicalIndex 返回规范棋盘 b 在 [0, NumCanonical()) 上的稠密下标（按 Index 的顺序编号）；
+// b 不是规范棋盘或子数不合法时 ok 为 false。
+func CanonicalIndex(b *game.Board) (idx int, ok bool) {
+	i, ok := Index(b)
+	if !ok {
+		return 0, false
+	}
+	return canonicalRank(i)
+}
+
+// canonicalRank 把 Index 为 i 的棋盘换算为稠密下标
+func canonicalRank(i int) (idx int, ok bool) {
+	canonOnce.Do(buildCanonical)
+	w, bit := i/64, uint64(1)<<(i%64)
+	if canonBits[w]&bit == 0 {
+		return 0, false
+	}
+	return int(canonRank[w]) + bits.OnesCount64(canonBits[w]&(bit-1)), true
+}
+
+// buildCanonical 按 Index 的顺序枚举全部子数合法的棋盘，标记其中的规范棋盘。
+// 占用格与黑子在占用格中的位置都按 colex 顺序（即掩码数值递增）枚举，与 Index 的排名一致。
+func buildCanonical() {
+	canonBits = make([]uint64, (NumPositions+63)/64)
+	i := 0
+	var pos [16]int
+	for nb := 0; nb <= 8; nb++ {
+		for _, nw := range []int{nb - 1, nb} {
+			if nw < 0 {
+				continue
+			}
+			n := nb + nw
+			for occ := uint32(1)<<n - 1; occ < 1<<16; occ = nextSubset(occ) {
+				k := 0
+				for m := occ; m != 0; m &= m - 1 {
+					pos[k] = bits.TrailingZeros32(m)
+					k++
+				}
+				for sel := uint32(1)<<nb - 1; sel < 1<<n; sel = nextSubset(sel) {
+					var black uint16
+					for m := sel; m != 0; m &= m - 1 {
+						black |= 1 << pos[bits.TrailingZeros32(m)]
+					}
+					if game.IsCanonical(black, uint16(occ)&^black) {
+						canonBits[i/64] |= 1 << (i % 64)
+					}
+					i++
+					if sel == 0 {
+						break
+					}
+				}
+				if occ == 0 {
+					break
+				}
+			}
+		}
+	}
+	canonRank = make([]uint32, len(canonBits))
+	for w, word := range canonBits {
+		canonRank[w] = uint32(numCanonical)
+		numCanonical += bits.OnesCount64(word)
+	}
+}
+
+// nextSubset 返回与 x 置位数相同、数值大于 x 的最小整数（Gosper 方法）；x 不能为 0。
+func nextSubset(x uint32) uint32 {
+	c := x & -x
+	r := x + c
+	return (r^x)>>2/c | r
+}
@@ -0,0 +1,249 @@
+// File solver/solver.go
+package solver
+
+import (
+	"fmt"
+	"math/bits"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/player"
+)
+
+// Outcome 表示局面在双方最优应对下的结果（以轮到走棋的一方为视角）。
+type Outcome uint8
+
+const (
+	Unknown Outcome = iota // 未求解（不可达局面）
+	Win
+	Loss
+	Draw
+)
+
+// String 返回 Outcome 的可读字符串。
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "Win"
+	case Loss:
+		return "Loss"
+	case Draw:
+		return "Draw"
+	default:
+		return "Unknown"
+	}
+}
+
+// Value 为单个局面的求解结果，占 1 字节：
+// 高 2 位为 Outcome，低 6 位为双方最优应对下距离终局的步数（ply）。
+// 胜方尽快取胜、负方尽量拖延；和棋取最快结束的和棋路线。
+type Value uint8
+
+// makeValue 组合结果与距离。
+func makeValue(o Outcome, dist int) Value {
+	return Value(o)<<6 | Value(dist)
+}
+
+// Outcome 返回该值的结果部分。
+func (v Value) Outcome() Outcome {
+	return Outcome(v >> 6)
+}
+
+// Distance 返回距离终局的步数。
+func (v Value) Distance() int {
+	return int(v & 0x3F)
+}
+
+// negate 把对手视角的值转换为己方视角，并加上一步。
+func (v Value) negate() Value {
+	switch v.Outcome() {
+	case Win:
+		return makeValue(Loss, v.Distance()+1)
+	case Loss:
+		return makeValue(Win, v.Distance()+1)
+	}
+	return makeValue(v.Outcome(), v.Distance()+1)
+}
+
+// better 判断 a 是否严格优于 b（均为同一方视角）。
+// 排序：快胜 > 慢胜 > 快和 > 慢和 > 慢负 > 快负。
+func better(a, b Value) bool {
+	return a.rank() > b.rank()
+}
+
+// rank 把 Value 映射为可比较的整数，越大越好。
+func (v Value) rank() int {
+	d := v.Distance()
+	switch v.Outcome() {
+	case Win:
+		return 200 - d
+	case Draw:
+		return 100 - d
+	case Loss:
+		return d
+	}
+	return -1
+}
+
+// Table 保存某一旋转配置下全部可达局面的求解结果。
+// 互为对称像的局面价值相同（见 game.CanonicalBoard），因此只为规范棋盘保存条目，
+// 以 CanonicalIndex 为下标，条目数约为 NumPositions 的四分之一；查询时先把局面变换为规范形式。
+// 镜像把两圈方向都反转，所以同一张表也能回答两圈方向都相反的配置（例如 cw/ccw 的表也用于 ccw/cw）。
+// 由 NewIndexTable 构造的表（版本 1 表库文件的布局）改以 Index 为下标。
+type Table struct {
+	DirOuter game.Direction
+	DirInner game.Direction
+	values   []Value // 以 CanonicalIndex 为下标；indexed 时以 Index 为下标
+	indexed  bool
+}
+
+// Solve 从空棋盘出发，穷举 (dirOuter, dirInner) 配置下全部可达局面并求解。
+func Solve(dirOuter, dirInner game.Direction) *Table {
+	t := &Table{
+		DirOuter: dirOuter,
+		DirInner: dirInner,
+		values:   make([]Value, NumCanonical()),
+	}
+	t.solve(game.NewGame(dirOuter, dirInner))
+	return t
+}
+
+// index 返回局面 g 的规范棋盘在表中的下标；g 的配置既不是表的配置也不是其镜像，
+// 或子数不合法时 ok 为 false。
+func (t *Table) index(g *game.GameState) (idx int, ok bool) {
+	b, _, ok := game.CanonicalBoard(g, t.DirOuter, t.DirInner)
+	if !ok {
+		return 0, false
+	}
+	if t.indexed {
+		return Index(b)
+	}
+	return CanonicalIndex(b)
+}
+
+// solve 记忆化递归求解一个未结束的局面，返回轮到走棋一方视角的值。
+func (t *Table) solve(g *game.GameState) Value {
+	idx, _ := t.index(g)
+	if v := t.values[idx]; v != 0 {
+		return v
+	}
+
+	var best Value
+	for _, mv := range g.GenerateMoves() {
+		next, v := t.child(g, mv)
+		if next != nil {
+			v = t.solve(next).negate()
+		}
+		if best == 0 || better(v, best) {
+			best = v
+		}
+	}
+	t.values[idx] = best
+	return best
+}
+
+// child 走 mv：若对局因此结束，返回 nil 与以 g 中走棋方为视角的终局值；
+// 否则返回走后的局面，由调用方继续求值。
+func (t *Table) child(g *game.GameState, mv game.Move) (*game.GameState, Value) {
+	next, res, _ := game.Play(g, mv)
+	if res.Over() {
+		return nil, terminalValue(res.Winner(), g.CurrentPlayer, 1)
+	}
+	return next, 0
+}
+
+// terminalValue 把已结束对局的胜者转换为 me 视角、距离为 dist 的值。
+func terminalValue(winner, me player.Color, dist int) Value {
+	switch winner {
+	case player.Empty:
+		return makeValue(Draw, dist)
+	case me:
+		return makeValue(Win, dist)
+	}
+	return makeValue(Loss, dist)
+}
+
+// NewTable 用已有的求解数据（例如从表库文件读出）构造 Table，
+// values 以 CanonicalIndex 为下标，长度必须等于 NumCanonical()。
+func NewTable(dirOuter, dirInner game.Direction, values []Value) (*Table, error) {
+	if len(values) != NumCanonical() {
+		return nil, fmt.Errorf("solver: table has %d entries, want %d", len(values), NumCanonical())
+	}
+	return &Table{DirOuter: dirOuter, DirInner: dirInner, values: values}, nil
+}
+
+// NewIndexTable 与 NewTable 相同，但 values 以 Index 为下标、长度必须等于 NumPositions
+// （版本 1 表库文件的布局，只会读取其中规范棋盘的条目）。
+func NewIndexTable(dirOuter, dirInner game.Direction, values []Value) (*Table, error) {
+	if len(values) != NumPositions {
+		return nil, fmt.Errorf("solver: table has %d entries, want %d", len(values), NumPositions)
+	}
+	return &Table{DirOuter: dirOuter, DirInner: dirInner, values: values, indexed: true}, nil
+}
+
+// Values 返回原始求解数据，调用方不应修改。下标为 CanonicalIndex；
+// 由 NewIndexTable 构造的表为 Index。
+func (t *Table) Values() []Value {
+	return t.values
+}
+
+// Compact 返回以 CanonicalIndex 为下标的表：t 已是这种布局时返回 t 本身，
+// 否则复制出其中规范棋盘的条目。
+func (t *Table) Compact() *Table {
+	if !t.indexed {
+		return t
+	}
+	values := make([]Value, 0, NumCanonical())
+	for w, word := range canonBits {
+		for m := word; m != 0; m &= m - 1 {
+			values = append(values, t.values[w*64+bits.TrailingZeros64(m)])
+		}
+	}
+	return &Table{DirOuter: t.DirOuter, DirInner: t.DirInner, values: values}
+}
+
+// Value 返回局面 g 以 g.CurrentPlayer 为视角的求解值；g 可以属于表的配置或其镜像配置。
+// 已结束的对局返回距离为 0 的结果；不可达局面或其他配置的局面返回 Outcome 为 Unknown 的值。
+func (t *Table) Value(g *game.GameState) Value {
+	if g.IsGameOver() {
+		return terminalValue(g.WinnerColor(), g.CurrentPlayer, 0)
+	}
+	idx, ok := t.index(g)
+	if !ok {
+		return 0
+	}
+	return t.values[idx]
+}
+
+// Probe 返回局面的求解值以及全部最优着法（走后能保持该值的着法）。
+func (t *Table) Probe(g *game.GameState) (Value, []game.Move) {
+	v := t.Value(g)
+	if g.IsGameOver() || v.Outcome() == Unknown {
+		return v, nil
+	}
+	var best []game.Move
+	for _, mv := range g.GenerateMoves() {
+		next, cv := t.child(g, mv)
+		if next != nil {
+			cv = t.Value(next).negate()
+		}
+		if cv == v {
+			best = append(best, mv)
+		}
+	}
+	return v, best
+}
+
+// Stats 统计已求解局面中胜、负、和的数量（只计规范局面）。
+func (t *Table) Stats() (wins, losses, draws int) {
+	for _, v := range t.Compact().values {
+		switch v.Outcome() {
+		case Win:
+			wins++
+		case Loss:
+			losses++
+		case Draw:
+			draws++
+		}
+	}
+	return
+}
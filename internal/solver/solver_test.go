@@ -0,0 +1,255 @@
+package solver
+
+import (
+	"math/bits"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/player"
+)
+
+// TestIndexBijection 子数合法的全部棋盘（可达局面的超集）映射到 [0, NumPositions) 且互不相同，
+// 因此 Index 是到该区间的双射；子数不合法的棋盘被拒绝。CanonicalIndex 同样是规范棋盘到
+// [0, NumCanonical()) 的双射，并保持 Index 的顺序。
+func TestIndexBijection(t *testing.T) {
+	seen := make([]bool, NumPositions)
+	canonIdx := make([]int, NumPositions) // Index → CanonicalIndex，非规范棋盘为 -1
+	count := 0
+	b := game.NewBoard()
+	for occ := 0; occ < 1<<16; occ++ {
+		nb := (bits.OnesCount16(uint16(occ)) + 1) / 2
+		// 枚举 occ 中恰有 nb 个黑子的全部子集
+		for black := occ; ; black = (black - 1) & occ {
+			if bits.OnesCount16(uint16(black)) == nb {
+				setMasks(b, uint16(black), uint16(occ&^black))
+				idx, ok := Index(b)
+				if !ok || idx < 0 || idx >= NumPositions {
+					t.Fatalf("Index(%016b, %016b) = %d, %v", black, occ&^black, idx, ok)
+				}
+				if seen[idx] {
+					t.Fatalf("Index %d repeated for black %016b white %016b", idx, black, occ&^black)
+				}
+				seen[idx] = true
+				count++
+				canonIdx[idx] = -1
+				ci, ok := CanonicalIndex(b)
+				if ok != game.IsCanonical(uint16(black), uint16(occ&^black)) {
+					t.Fatalf("CanonicalIndex(%016b, %016b) ok = %v", black, occ&^black, ok)
+				}
+				if ok {
+					canonIdx[idx] = ci
+				}
+			}
+			if black == 0 {
+				break
+			}
+		}
+	}
+	if count != NumPositions {
+		t.Fatalf("enumerated %d boards, NumPositions = %d", count, NumPositions)
+	}
+	next := 0
+	for idx, ci := range canonIdx {
+		if ci >= 0 {
+			if ci != next {
+				t.Fatalf("Index %d: CanonicalIndex %d, want %d", idx, ci, next)
+			}
+			next++
+		}
+	}
+	if next != NumCanonical() {
+		t.Fatalf("%d canonical boards, NumCanonical = %d", next, NumCanonical())
+	}
+
+	for _, masks := range [][2]uint16{{0, 1}, {0b111, 0b1}, {0x1FF, 0xFE00}} {
+		setMasks(b, masks[0], masks[1])
+		if _, ok := Index(b); ok {
+			t.Fatalf("Index accepted black %016b white %016b", masks[0], masks[1])
+		}
+	}
+}
+
+// setMasks 按黑白位掩码重置棋盘 b
+func setMasks(b *game.Board, black, white uint16) {
+	for i := 0; i < 16; i++ {
+		col := player.Empty
+		switch {
+		case black&(1<<i) != 0:
+			col = player.Black
+		case white&(1<<i) != 0:
+			col = player.White
+		}
+		b.Set(i/4, i%4, col)
+	}
+}
+
+var (
+	tablesOnce sync.Once
+	tables     [2]*Table // 外圈顺时针、内圈为下标方向的两张表；其余两种配置是它们的镜像
+)
+
+// tableFor 返回能回答 (dirOuter, dirInner) 配置的表，整个测试只求解一次。
+func tableFor(dirOuter, dirInner game.Direction) *Table {
+	tablesOnce.Do(func() {
+		for _, dirInner := range []game.Direction{game.Clockwise, game.CounterClockwise} {
+			tables[dirInner] = Solve(game.Clockwise, dirInner)
+		}
+	})
+	_, dirInner = game.CanonicalDirections(dirOuter, dirInner)
+	return tables[dirInner]
+}
+
+// bruteForce 与 game 包测试中的同名函数相同：只借助 ApplyMove 穷举对局，返回走棋方视角的结果
+// （1 胜、0 和、-1 负）及双方最优应对下到终局的步数（胜方求快、负方求慢）。
+func bruteForce(g *game.GameState) (result, plies int) {
+	result = -2
+	for _, mv := range g.GenerateMoves() {
+		next := g.Clone()
+		if err := next.ApplyMove(mv.Row, mv.Col); err != nil {
+			panic(err)
+		}
+		var r, p int
+		switch {
+		case !next.IsGameOver():
+			r, p = bruteForce(next)
+			r = -r
+		case next.WinnerColor() == g.CurrentPlayer:
+			r = 1
+		case next.WinnerColor() != 0:
+			r = -1
+		}
+		p++
+		better := r > result ||
+			r == result && r == 1 && p < plies ||
+			r == result && r == -1 && p > plies
+		if better {
+			result, plies = r, p
+		}
+	}
+	return result, plies
+}
+
+// checkValue 比对求解值与穷举结果；和棋的步数取决于穷举顺序，不做比较。
+func checkValue(t *testing.T, g *game.GameState, v Value) {
+	t.Helper()
+	r, p := bruteForce(g)
+	want := map[int]Outcome{1: Win, 0: Draw, -1: Loss}[r]
+	if v.Outcome() != want || want != Draw && v.Distance() != p {
+		t.Fatalf("%s: value %v/%d, brute force %v/%d", game.FormatPosition(g), v.Outcome(), v.Distance(), want, p)
+	}
+}
+
+// TestSolveMatchesBruteForce 在四种旋转配置的随机残局上，Value 与 Probe 给出的最优着法都与穷举一致。
+func TestSolveMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for _, dirs := range [][2]game.Direction{
+		{game.Clockwise, game.Clockwise},
+		{game.Clockwise, game.CounterClockwise},
+		{game.CounterClockwise, game.Clockwise},
+		{game.CounterClockwise, game.CounterClockwise},
+	} {
+		tb := tableFor(dirs[0], dirs[1])
+		for checked := 0; checked < 60; {
+			g := game.NewGame(dirs[0], dirs[1])
+			for len(g.GenerateMoves()) > 7 && !g.IsGameOver() {
+				moves := g.GenerateMoves()
+				mv := moves[rng.Intn(len(moves))]
+				_ = g.ApplyMove(mv.Row, mv.Col)
+			}
+			if g.IsGameOver() {
+				continue
+			}
+			checked++
+
+			v, best := tb.Probe(g)
+			if v != tb.Value(g) {
+				t.Fatalf("Probe value %v, Value %v", v, tb.Value(g))
+			}
+			checkValue(t, g, v)
+			if len(best) == 0 {
+				t.Fatalf("%s: no best moves", game.FormatPosition(g))
+			}
+			for _, mv := range best {
+				next, res, _ := game.Play(g, mv)
+				if res.Over() {
+					if tb.Value(next).Outcome() == Unknown || v.Distance() != 1 {
+						t.Fatalf("%s: best move %v ends the game but value is %v/%d",
+							game.FormatPosition(g), mv, v.Outcome(), v.Distance())
+					}
+					continue
+				}
+				checkValue(t, next, tb.Value(next))
+				if tb.Value(next).negate() != v {
+					t.Fatalf("%s: best move %v leads to %v", game.FormatPosition(g), mv, tb.Value(next))
+				}
+			}
+		}
+	}
+}
+
+// TestValueRejectsOtherConfiguration 其他旋转配置的局面返回 Unknown。
+func TestValueRejectsOtherConfiguration(t *testing.T) {
+	tb := tableFor(game.Clockwise, game.Clockwise)
+	g := game.NewGame(game.Clockwise, game.CounterClockwise)
+	_ = g.ApplyMove(0, 0)
+	if v := tb.Value(g); v.Outcome() != Unknown {
+		t.Fatalf("value for another configuration: %v", v.Outcome())
+	}
+	if v := tb.Value(game.NewGame(game.Clockwise, game.Clockwise)); v.Outcome() == Unknown {
+		t.Fatal("empty board not solved")
+	}
+}
+
+// TestIndexTable 按 Index 排列的表（版本 1 表库文件的布局）给出同样的求解值，Compact 还原出原表。
+func TestIndexTable(t *testing.T) {
+	tb := tableFor(game.Clockwise, game.CounterClockwise)
+	full := make([]Value, NumPositions)
+	for w, word := range canonBits {
+		for m := word; m != 0; m &= m - 1 {
+			idx := w*64 + bits.TrailingZeros64(m)
+			ci, _ := canonicalRank(idx)
+			full[idx] = tb.values[ci]
+		}
+	}
+	it, err := NewIndexTable(tb.DirOuter, tb.DirInner, full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTable(tb.DirOuter, tb.DirInner, full); err == nil {
+		t.Fatal("NewTable accepted an Index-ordered table")
+	}
+
+	rng := rand.New(rand.NewSource(8))
+	for n := 0; n < 2000; n++ {
+		g := game.NewGame(game.CounterClockwise, game.Clockwise)
+		if n%2 == 0 {
+			g = game.NewGame(game.Clockwise, game.CounterClockwise)
+		}
+		for i := rng.Intn(14); i > 0 && !g.IsGameOver(); i-- {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if v, want := it.Value(g), tb.Value(g); v != want {
+			t.Fatalf("%s: Index table %v/%d, table %v/%d", game.FormatPosition(g),
+				v.Outcome(), v.Distance(), want.Outcome(), want.Distance())
+		}
+	}
+
+	c := it.Compact()
+	if len(c.Values()) != NumCanonical() || c.Compact() != c {
+		t.Fatalf("Compact has %d entries", len(c.Values()))
+	}
+	for i, v := range c.Values() {
+		if v != tb.values[i] {
+			t.Fatalf("Compact entry %d = %v, want %v", i, v, tb.values[i])
+		}
+	}
+	w1, l1, d1 := it.Stats()
+	w2, l2, d2 := tb.Stats()
+	if w1 != w2 || l1 != l2 || d1 != d2 {
+		t.Fatalf("Stats %d/%d/%d, want %d/%d/%d", w1, l1, d1, w2, l2, d2)
+	}
+}
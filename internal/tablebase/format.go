@@ -0,0 +1,176 @@
+// File tablebase/format.go
+package tablebase
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+)
+
+// 表库文件布局（小端序）：
+//
+//	偏移  长度  内容
+//	0     4     魔数 "TLTB"
+//	4     2     格式版本（当前为 Version）
+//	6     1     外圈方向 DirOuter
+//	7     1     内圈方向 DirInner
+//	8     4     条目数（必须等于 solver.NumCanonical()）
+//	12    4     数据区的 CRC-32（IEEE）
+//	16    n     条目数据：每个规范局面 1 字节 solver.Value，以 solver.CanonicalIndex 为下标
+//
+// 一个文件只保存一种旋转配置，文件名由 FileName 给出；镜像配置共用同一文件（见 OpenDir）。
+// 版本 1 的文件为每个子数合法的局面保存 1 字节，条目数为 solver.NumPositions、以 solver.Index 为下标
+// （约 9.7 MB，版本 2 约 2.4 MB），读取时按 solver.NewIndexTable 构造，仍可使用。
+
+const (
+	magic      = "TLTB"
+	Version    = 2
+	headerSize = 16
+)
+
+var (
+	ErrBadMagic   = errors.New("tablebase: not a tablebase file")
+	ErrVersion    = errors.New("tablebase: unsupported format version")
+	ErrCorrupt    = errors.New("tablebase: checksum mismatch")
+	ErrWrongTable = errors.New("tablebase: entry count does not match solver")
+)
+
+// header 为文件头。
+type header struct {
+	Version  uint16
+	DirOuter game.Direction
+	DirInner game.Direction
+	Count    uint32
+	CRC      uint32
+}
+
+// FileName 返回某一旋转配置对应的表库文件名。
+func FileName(dirOuter, dirInner game.Direction) string {
+	return fmt.Sprintf("tlc-o%d-i%d.tltb", dirOuter, dirInner)
+}
+
+// encodeHeader 把文件头编码为 headerSize 字节。
+func encodeHeader(h header) []byte {
+	buf := make([]byte, headerSize)
+	copy(buf, magic)
+	binary.LittleEndian.PutUint16(buf[4:], h.Version)
+	buf[6] = byte(h.DirOuter)
+	buf[7] = byte(h.DirInner)
+	binary.LittleEndian.PutUint32(buf[8:], h.Count)
+	binary.LittleEndian.PutUint32(buf[12:], h.CRC)
+	return buf
+}
+
+// decodeHeader 解析并校验文件头（不含 CRC 校验）。
+func decodeHeader(buf []byte) (header, error) {
+	var h header
+	if len(buf) < headerSize || string(buf[:4]) != magic {
+		return h, ErrBadMagic
+	}
+	h.Version = binary.LittleEndian.Uint16(buf[4:])
+	if h.Version < 1 || h.Version > Version {
+		return h, fmt.Errorf("%w: %d", ErrVersion, h.Version)
+	}
+	h.DirOuter = game.Direction(buf[6])
+	h.DirInner = game.Direction(buf[7])
+	if h.DirOuter > game.CounterClockwise || h.DirInner > game.CounterClockwise {
+		return h, fmt.Errorf("tablebase: invalid directions %d/%d", buf[6], buf[7])
+	}
+	h.Count = binary.LittleEndian.Uint32(buf[8:])
+	want := solver.NumCanonical()
+	if h.Version == 1 {
+		want = solver.NumPositions
+	}
+	if int(h.Count) != want {
+		return h, ErrWrongTable
+	}
+	h.CRC = binary.LittleEndian.Uint32(buf[12:])
+	return h, nil
+}
+
+// valueBytes 不拷贝地以字节切片的形式查看求解数据（solver.Value 为单字节类型）。
+func valueBytes(values []solver.Value) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&values[0])), len(values))
+}
+
+// bytesAsValues 不拷贝地把字节切片视为 solver.Value 切片。
+func bytesAsValues(b []byte) []solver.Value {
+	if len(b) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*solver.Value)(unsafe.Pointer(&b[0])), len(b))
+}
+
+// newTable 按文件头的版本用 data 构造求解表，data 的长度已由 decodeHeader 校验。
+func newTable(h header, data []byte) (*solver.Table, error) {
+	if h.Version == 1 {
+		return solver.NewIndexTable(h.DirOuter, h.DirInner, bytesAsValues(data))
+	}
+	return solver.NewTable(h.DirOuter, h.DirInner, bytesAsValues(data))
+}
+
+// Write 把求解表 t 以当前版本的表库格式写入 w（读自版本 1 文件的表会先转换为规范布局）。
+func Write(w io.Writer, t *solver.Table) error {
+	data := valueBytes(t.Compact().Values())
+	h := header{
+		Version:  Version,
+		DirOuter: t.DirOuter,
+		DirInner: t.DirInner,
+		Count:    uint32(len(data)),
+		CRC:      crc32.ChecksumIEEE(data),
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(encodeHeader(h)); err != nil {
+		return err
+	}
+	if _, err := bw.Write(data); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Save 把求解表写入目录 dir 下的标准文件名，返回文件路径。
+func Save(dir string, t *solver.Table) (string, error) {
+	path := filepath.Join(dir, FileName(t.DirOuter, t.DirInner))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := Write(f, t); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// Read 以流式方式从 r 读取整张表库到内存。
+func Read(r io.Reader) (*solver.Table, error) {
+	buf := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("tablebase: read header: %w", err)
+	}
+	h, err := decodeHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, h.Count)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("tablebase: read entries: %w", err)
+	}
+	if crc32.ChecksumIEEE(data) != h.CRC {
+		return nil, ErrCorrupt
+	}
+	return newTable(h, data)
+}
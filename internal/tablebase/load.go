@@ -0,0 +1,74 @@
+// File tablebase/load.go
+package tablebase
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+)
+
+// File 是一个已打开的表库文件。在支持的平台上数据通过内存映射读取，
+// 否则整体读入内存；使用完毕后应调用 Close。
+type File struct {
+	*solver.Table
+	data  []byte // 映射或读入的整个文件
+	unmap func([]byte) error
+}
+
+// Open 打开表库文件 path，校验文件头与校验和。
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, unmap, err := mapFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("tablebase: load %s: %w", path, err)
+	}
+	tf := &File{data: data, unmap: unmap}
+	h, err := decodeHeader(data)
+	if err == nil && len(data) != headerSize+int(h.Count) {
+		err = fmt.Errorf("tablebase: %s is truncated", path)
+	}
+	if err == nil && crc32.ChecksumIEEE(data[headerSize:]) != h.CRC {
+		err = ErrCorrupt
+	}
+	if err == nil {
+		tf.Table, err = newTable(h, data[headerSize:])
+	}
+	if err != nil {
+		tf.Close()
+		return nil, err
+	}
+	return tf, nil
+}
+
+// OpenDir 打开目录 dir 中对应旋转配置的表库文件；该文件不存在时改用镜像配置
+// （两圈方向都相反）的文件，其中的表同样能回答本配置的局面（见 solver.Table）。
+func OpenDir(dir string, dirOuter, dirInner game.Direction) (*File, error) {
+	f, err := Open(filepath.Join(dir, FileName(dirOuter, dirInner)))
+	if errors.Is(err, fs.ErrNotExist) {
+		if mf, merr := Open(filepath.Join(dir, FileName(dirOuter.Reverse(), dirInner.Reverse()))); merr == nil {
+			return mf, nil
+		}
+	}
+	return f, err
+}
+
+// Close 释放映射的内存，之后不能再查询该表。
+func (f *File) Close() error {
+	var err error
+	if f.unmap != nil && f.data != nil {
+		err = f.unmap(f.data)
+	}
+	f.data, f.Table = nil, nil
+	return err
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package tablebase
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile 在不支持内存映射的平台上把整个文件读入内存。
+func mapFile(f *os.File) ([]byte, func([]byte) error, error) {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package tablebase
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile 以只读方式内存映射整个文件。
+func mapFile(f *os.File) ([]byte, func([]byte) error, error) {
+	st, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if st.Size() == 0 {
+		return nil, nil, ErrBadMagic
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(st.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, syscall.Munmap, nil
+}
@@ -0,0 +1,87 @@
+// File tablebase/player.go
+package tablebase
+
+import (
+	"context"
+	"sync"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+)
+
+// Player 通过查询表库完美对弈，可作为检验其他引擎的基准。
+// 目录中缺少对应旋转配置的表库文件时，交给 fallback 选着。
+type Player struct {
+	dir      string
+	fallback game.Player
+
+	mu     sync.Mutex
+	files  map[[2]game.Direction]*File
+	failed map[[2]game.Direction]error // 打开失败的配置，避免重复尝试
+}
+
+// NewPlayer 创建一个从目录 dir 加载表库的 Player；查不到表库时由 fallback 选着。
+// fallback 的随机源固定时，缺少表库文件的对局同样可以复现。
+func NewPlayer(dir string, fallback game.Player) *Player {
+	return &Player{
+		dir:      dir,
+		fallback: fallback,
+		files:    make(map[[2]game.Direction]*File),
+		failed:   make(map[[2]game.Direction]error),
+	}
+}
+
+// table 返回对应旋转配置的表库，按需打开；不可用时返回 nil。
+func (p *Player) table(dirOuter, dirInner game.Direction) *solver.Table {
+	key := [2]game.Direction{dirOuter, dirInner}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.files[key]; ok {
+		return f.Table
+	}
+	if _, ok := p.failed[key]; ok {
+		return nil
+	}
+	f, err := OpenDir(p.dir, dirOuter, dirInner)
+	if err != nil {
+		p.failed[key] = err
+		return nil
+	}
+	p.files[key] = f
+	return f.Table
+}
+
+// Err 返回打开某一旋转配置的表库时遇到的错误（未尝试或成功时为 nil）。
+func (p *Player) Err(dirOuter, dirInner game.Direction) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.failed[[2]game.Direction{dirOuter, dirInner}]
+}
+
+// ChooseMove 实现 game.Player：优先取表库给出的最优着法，
+// 表库缺失或局面不在表中时交给 fallback。
+func (p *Player) ChooseMove(ctx context.Context, g *game.GameState) (game.Move, error) {
+	if err := ctx.Err(); err != nil {
+		return game.Move{}, err
+	}
+	if t := p.table(g.DirOuter, g.DirInner); t != nil {
+		if _, best := t.Probe(g); len(best) > 0 {
+			return best[0], nil
+		}
+	}
+	return p.fallback.ChooseMove(ctx, g)
+}
+
+// Close 关闭所有已打开的表库文件。
+func (p *Player) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var first error
+	for key, f := range p.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(p.files, key)
+	}
+	return first
+}
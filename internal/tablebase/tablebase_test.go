@@ -0,0 +1,283 @@
+package tablebase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/solver"
+)
+
+// randomValues 返回 n 个随机条目：格式层不关心数据含义，无需真正求解。
+func randomValues(n int, seed int64) []solver.Value {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]solver.Value, n)
+	for i := range values {
+		values[i] = solver.Value(rng.Intn(256))
+	}
+	return values
+}
+
+// randomTable 返回一张填满随机条目的表。
+func randomTable(t *testing.T, dirOuter, dirInner game.Direction) *solver.Table {
+	t.Helper()
+	values := randomValues(solver.NumCanonical(), int64(dirOuter)*2+int64(dirInner)+1)
+	tb, err := solver.NewTable(dirOuter, dirInner, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tb
+}
+
+// sameTable 比较配置与全部条目
+func sameTable(t *testing.T, got, want *solver.Table) {
+	t.Helper()
+	if got.DirOuter != want.DirOuter || got.DirInner != want.DirInner {
+		t.Fatalf("directions %v/%v, want %v/%v", got.DirOuter, got.DirInner, want.DirOuter, want.DirInner)
+	}
+	if !bytes.Equal(valueBytes(got.Values()), valueBytes(want.Values())) {
+		t.Fatal("entries differ")
+	}
+}
+
+// encode 返回 tb 以表库格式编码后的字节
+func encode(t *testing.T, tb *solver.Table) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, tb); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeFile 把 data 写入临时目录中 name 文件，返回路径
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWriteRead(t *testing.T) {
+	tb := randomTable(t, game.Clockwise, game.CounterClockwise)
+	data := encode(t, tb)
+	if len(data) != headerSize+solver.NumCanonical() {
+		t.Fatalf("encoded %d bytes", len(data))
+	}
+	got, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameTable(t, got, tb)
+}
+
+func TestSaveOpen(t *testing.T) {
+	dir := t.TempDir()
+	tb := randomTable(t, game.CounterClockwise, game.Clockwise)
+	path, err := Save(dir, tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != FileName(game.CounterClockwise, game.Clockwise) {
+		t.Fatalf("saved as %s", path)
+	}
+	f, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameTable(t, f.Table, tb)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if f.Table != nil {
+		t.Fatal("table still set after Close")
+	}
+}
+
+// TestBadFiles 损坏、魔数错误、版本不支持与截断的文件都被拒绝，Read 与 Open 的结论一致。
+func TestBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := encode(t, randomTable(t, game.Clockwise, game.Clockwise))
+	modified := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), good...))
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want error // nil 表示只要求出错
+	}{
+		{"corrupt", modified(func(b []byte) []byte { b[headerSize+12345] ^= 0x40; return b }), ErrCorrupt},
+		{"magic", modified(func(b []byte) []byte { copy(b, "TLXX"); return b }), ErrBadMagic},
+		{"version0", modified(func(b []byte) []byte { b[4], b[5] = 0, 0; return b }), ErrVersion},
+		{"version", modified(func(b []byte) []byte { b[4], b[5] = Version+1, 0; return b }), ErrVersion},
+		{"count", modified(func(b []byte) []byte { b[8]++; return b }), ErrWrongTable},
+		{"header", good[:headerSize-3], nil},
+		{"entries", good[:len(good)-100], nil},
+		{"empty", nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, rerr := Read(bytes.NewReader(c.data))
+			f, oerr := Open(writeFile(t, dir, c.name+".tltb", c.data))
+			if oerr == nil {
+				f.Close()
+			}
+			for _, err := range []error{rerr, oerr} {
+				if err == nil || c.want != nil && !errors.Is(err, c.want) {
+					t.Fatalf("Read: %v, Open: %v; want %v", rerr, oerr, c.want)
+				}
+			}
+		})
+	}
+	if _, err := Read(bytes.NewReader(good[:len(good)-100])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("truncated Read: %v", err)
+	}
+}
+
+// TestVersion1 版本 1 的文件（以 solver.Index 为下标）仍可读取；重新写出时转换为版本 2，文件更小。
+func TestVersion1(t *testing.T) {
+	tb, err := solver.NewIndexTable(game.Clockwise, game.Clockwise, randomValues(solver.NumPositions, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := valueBytes(tb.Values())
+	v1 := append(encodeHeader(header{
+		Version:  1,
+		DirOuter: tb.DirOuter,
+		DirInner: tb.DirInner,
+		Count:    uint32(len(data)),
+		CRC:      crc32.ChecksumIEEE(data),
+	}), data...)
+	got, err := Read(bytes.NewReader(v1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameTable(t, got, tb)
+	dir := t.TempDir()
+	v1Path := writeFile(t, dir, "v1.tltb", v1)
+	f, err := Open(v1Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sameTable(t, f.Table, tb)
+
+	v2Path, err := Save(dir, f.Table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1Info, err := os.Stat(v1Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2Info, err := os.Stat(v2Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2Info.Size() != int64(headerSize+solver.NumCanonical()) || v2Info.Size() >= v1Info.Size() {
+		t.Fatalf("version 2 file has %d bytes, version 1 file %d", v2Info.Size(), v1Info.Size())
+	}
+	f2, err := Open(v2Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	sameTable(t, f2.Table, tb.Compact())
+}
+
+// TestOpenDirMirror 缺少本配置的文件时 OpenDir 改用镜像配置的文件；两者都缺时返回 fs.ErrNotExist。
+func TestOpenDirMirror(t *testing.T) {
+	dir := t.TempDir()
+	tb := randomTable(t, game.Clockwise, game.CounterClockwise)
+	if _, err := Save(dir, tb); err != nil {
+		t.Fatal(err)
+	}
+	for _, dirs := range [][2]game.Direction{
+		{game.Clockwise, game.CounterClockwise},
+		{game.CounterClockwise, game.Clockwise},
+	} {
+		f, err := OpenDir(dir, dirs[0], dirs[1])
+		if err != nil {
+			t.Fatalf("OpenDir %v/%v: %v", dirs[0], dirs[1], err)
+		}
+		sameTable(t, f.Table, tb)
+		f.Close()
+	}
+	if _, err := OpenDir(dir, game.Clockwise, game.Clockwise); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("OpenDir without files: %v", err)
+	}
+}
+
+// TestPlayer 有表库时（包括镜像配置）走表库的最优着法，缺少文件时退回搜索并记录错误。
+func TestPlayer(t *testing.T) {
+	dir := t.TempDir()
+	tb := solver.Solve(game.Clockwise, game.CounterClockwise)
+	if _, err := Save(dir, tb); err != nil {
+		t.Fatal(err)
+	}
+	p := NewPlayer(dir, game.NewEnginePlayer(game.Options{TTBits: 12, Rand: rand.New(rand.NewSource(1))}, game.Limits{Depth: 4}))
+	defer p.Close()
+
+	rng := rand.New(rand.NewSource(3))
+	for n := 0; n < 20; n++ {
+		g := game.NewGame(game.CounterClockwise, game.Clockwise)
+		for i := 0; i < 6 && !g.IsGameOver(); i++ {
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+		if g.IsGameOver() {
+			continue
+		}
+		mv, err := p.ChooseMove(context.Background(), g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, best := tb.Probe(g)
+		found := false
+		for _, b := range best {
+			found = found || b == mv
+		}
+		if !found {
+			t.Fatalf("%s: played %v, best %v", game.FormatPosition(g), mv, best)
+		}
+	}
+	if err := p.Err(game.CounterClockwise, game.Clockwise); err != nil {
+		t.Fatal(err)
+	}
+
+	g := game.NewGame(game.Clockwise, game.Clockwise)
+	mv, err := p.ChooseMove(context.Background(), g)
+	if err != nil || !g.Board.IsEmpty(mv.Row, mv.Col) {
+		t.Fatalf("fallback search played %v, %v", mv, err)
+	}
+	if err := p.Err(game.Clockwise, game.Clockwise); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Err for a missing table: %v", err)
+	}
+
+	// 退回搜索使用传入的 Player：随机源相同时着法相同
+	seeded := func() *Player {
+		return NewPlayer(dir, game.NewEnginePlayer(game.Options{TTBits: 12, Rand: rand.New(rand.NewSource(7))}, game.Limits{Depth: 3}))
+	}
+	p1, p2 := seeded(), seeded()
+	defer p1.Close()
+	defer p2.Close()
+	for i := 0; i < 8 && !g.IsGameOver(); i++ {
+		mv1, err1 := p1.ChooseMove(context.Background(), g)
+		mv2, err2 := p2.ChooseMove(context.Background(), g)
+		if err1 != nil || err2 != nil || mv1 != mv2 {
+			t.Fatalf("seeded fallbacks played %v (%v) and %v (%v)", mv1, err1, mv2, err2)
+		}
+		_ = g.ApplyMove(mv1.Row, mv1.Col)
+	}
+}
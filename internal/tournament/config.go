@@ -0,0 +1,128 @@
+// File tournament/config.go
+package tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/match"
+)
+
+// 赛制
+const (
+	RoundRobin = "roundrobin"
+	Swiss      = "swiss"
+)
+
+// Config 为赛事配置，通常从 JSON 文件读入，例如：
+//
+//	{
+//	  "format": "swiss",
+//	  "rounds": 7,
+//	  "rotations": ["cw cw", "ccw cw"],
+//	  "openingPlies": 2,
+//	  "concurrency": 4,
+//	  "engines": [
+//	    {"name": "ab6", "spec": "alphabeta:depth=6"},
+//	    {"name": "mcts", "spec": "mcts:iterations=20000"}
+//	  ]
+//	}
+type Config struct {
+	Format       string      `json:"format"`                 // roundrobin | swiss
+	Cycles       int         `json:"cycles,omitempty"`       // 循环赛的循环次数，0 表示 2（双循环，先后手各一次）
+	Rounds       int         `json:"rounds,omitempty"`       // 瑞士制的轮数
+	Rotations    []string    `json:"rotations,omitempty"`    // 使用的旋转配置，写作 "外圈 内圈"，空表示全部四种
+	OpeningPlies int         `json:"openingPlies,omitempty"` // 随机开局步数
+	Seed         int64       `json:"seed,omitempty"`         // 随机种子，0 表示开赛时按当前时间选取
+	Concurrency  int         `json:"concurrency,omitempty"`  // 同时进行的对局数，小于 1 时为 1
+	Engines      []EngineDef `json:"engines"`
+}
+
+// EngineDef 为一个参赛引擎：Name 用于成绩表，Spec 为引擎说明（由调用方解释）。
+type EngineDef struct {
+	Name string `json:"name"`
+	Spec string `json:"spec"`
+}
+
+var ErrConfig = errors.New("tournament: invalid config")
+
+// LoadConfig 读取并校验 JSON 配置文件。
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate 检查配置是否完整、合法。
+func (c *Config) Validate() error {
+	switch c.Format {
+	case RoundRobin:
+		if c.Cycles < 0 {
+			return fmt.Errorf("%w: cycles must be >= 0", ErrConfig)
+		}
+	case Swiss:
+		if c.Rounds < 1 {
+			return fmt.Errorf("%w: swiss needs rounds >= 1", ErrConfig)
+		}
+	default:
+		return fmt.Errorf("%w: unknown format %q", ErrConfig, c.Format)
+	}
+	if len(c.Engines) < 2 {
+		return fmt.Errorf("%w: need at least two engines", ErrConfig)
+	}
+	names := map[string]bool{}
+	for _, e := range c.Engines {
+		if e.Name == "" || names[e.Name] {
+			return fmt.Errorf("%w: engine names must be unique and non-empty (%q)", ErrConfig, e.Name)
+		}
+		names[e.Name] = true
+	}
+	if c.OpeningPlies < 0 || c.OpeningPlies > match.MaxRandomPlies {
+		return fmt.Errorf("%w: openingPlies must be between 0 and %d", ErrConfig, match.MaxRandomPlies)
+	}
+	_, err := c.rotations()
+	return err
+}
+
+// rotations 解析 Rotations，空时返回全部四种
+func (c *Config) rotations() ([][2]game.Direction, error) {
+	if len(c.Rotations) == 0 {
+		return append([][2]game.Direction(nil), match.Rotations[:]...), nil
+	}
+	out := make([][2]game.Direction, len(c.Rotations))
+	for i, s := range c.Rotations {
+		f := strings.Fields(s)
+		if len(f) != 2 {
+			return nil, fmt.Errorf("%w: rotation %q: want \"outer inner\"", ErrConfig, s)
+		}
+		for j := range f {
+			d, err := game.ParseDirection(f[j])
+			if err != nil {
+				return nil, fmt.Errorf("%w: rotation %q: %v", ErrConfig, s, err)
+			}
+			out[i][j] = d
+		}
+	}
+	return out, nil
+}
+
+// cycles 返回循环赛的循环次数
+func (c *Config) cycles() int {
+	if c.Cycles == 0 {
+		return 2
+	}
+	return c.Cycles
+}
@@ -0,0 +1,152 @@
+// File tournament/crosstable.go
+package tournament
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Standing 为一个引擎的成绩。
+type Standing struct {
+	Engine              int // 引擎下标
+	Name                string
+	Points              float64
+	Wins, Draws, Losses int
+	Byes                int
+	SB                  float64   // Sonneborn-Berger 分：所胜对手积分之和加所和对手积分之半
+	Versus              []float64 // Versus[j] 为对引擎 j 的得分
+	Played              []int     // Played[j] 为对引擎 j 的已完成局数
+}
+
+// Games 返回已完成的对局数（不含轮空）。
+func (s Standing) Games() int {
+	return s.Wins + s.Draws + s.Losses
+}
+
+// Standings 返回按积分、SB 分排序的成绩。
+func (t *Tournament) Standings() []Standing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.standings()
+}
+
+// standings 计算成绩（调用方持有 mu）
+func (t *Tournament) standings() []Standing {
+	n := len(t.engines)
+	st := make([]Standing, n)
+	for i := range st {
+		st[i] = Standing{Engine: i, Name: t.cfg.Engines[i].Name, Versus: make([]float64, n), Played: make([]int, n)}
+	}
+	add := func(i, j int, pts float64) {
+		st[i].Points += pts
+		st[i].Versus[j] += pts
+		st[i].Played[j]++
+		switch pts {
+		case 1:
+			st[i].Wins++
+		case 0:
+			st[i].Losses++
+		default:
+			st[i].Draws++
+		}
+	}
+	for _, round := range t.rounds {
+		for _, p := range round {
+			switch {
+			case !p.Done:
+			case p.White == Bye:
+				st[p.Black].Points++
+				st[p.Black].Byes++
+			default:
+				pts := p.blackPoints()
+				add(p.Black, p.White, pts)
+				add(p.White, p.Black, 1-pts)
+			}
+		}
+	}
+	for i := range st {
+		for j := range st {
+			st[i].SB += st[i].Versus[j] * st[j].Points
+		}
+	}
+	sort.SliceStable(st, func(a, b int) bool {
+		if st[a].Points != st[b].Points {
+			return st[a].Points > st[b].Points
+		}
+		return st[a].SB > st[b].SB
+	})
+	return st
+}
+
+// WriteCrosstable 把成绩表写入 w。
+func (t *Tournament) WriteCrosstable(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.writeCrosstable(w)
+}
+
+// writeCrosstable 写成绩表（调用方持有 mu）
+func (t *Tournament) writeCrosstable(w io.Writer) error {
+	st := t.standings()
+	done, total := 0, 0
+	for _, round := range t.rounds {
+		for _, p := range round {
+			if p.White == Bye {
+				continue
+			}
+			total++
+			if p.Done {
+				done++
+			}
+		}
+	}
+	var buf bytes.Buffer
+	switch t.cfg.Format {
+	case Swiss:
+		fmt.Fprintf(&buf, "Swiss, round %d of %d, %d/%d games played\n\n", len(t.rounds), t.cfg.Rounds, done, total)
+	default:
+		fmt.Fprintf(&buf, "Round robin, %d cycle(s), %d/%d games played\n\n", t.cfg.cycles(), done, total)
+	}
+
+	width := len("Name")
+	for _, s := range st {
+		width = max(width, len(s.Name))
+	}
+	fmt.Fprintf(&buf, "%4s  %-*s  %6s  %5s  %4s %4s %4s  %7s |", "Rank", width, "Name", "Points", "Games", "+", "=", "-", "SB")
+	for i := range st {
+		fmt.Fprintf(&buf, " %5d", i+1)
+	}
+	buf.WriteString("\n")
+	fmt.Fprintf(&buf, "%s\n", strings.Repeat("-", 4+2+width+2+6+2+5+2+14+2+7+2+6*len(st)))
+	for rank, s := range st {
+		fmt.Fprintf(&buf, "%4d  %-*s  %6.1f  %5d  %4d %4d %4d  %7.2f |",
+			rank+1, width, s.Name, s.Points, s.Games(), s.Wins, s.Draws, s.Losses, s.SB)
+		for _, o := range st {
+			switch {
+			case o.Engine == s.Engine:
+				fmt.Fprintf(&buf, " %5s", "x")
+			case s.Played[o.Engine] == 0:
+				fmt.Fprintf(&buf, " %5s", ".")
+			default:
+				fmt.Fprintf(&buf, " %5.1f", s.Versus[o.Engine])
+			}
+		}
+		buf.WriteString("\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeCrosstableFile 重写 crosstable.txt（调用方持有 mu）
+func (t *Tournament) writeCrosstableFile() error {
+	var buf bytes.Buffer
+	if err := t.writeCrosstable(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(t.dir, crosstableFile), buf.Bytes(), 0o644)
+}
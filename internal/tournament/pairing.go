@@ -0,0 +1,185 @@
+// File tournament/pairing.go
+package tournament
+
+import (
+	"sort"
+)
+
+// Bye 作为 Pairing.White 时表示该轮 Black 一方轮空。
+const Bye = -1
+
+// Pairing 为一局（或一次轮空）的安排与结果。
+type Pairing struct {
+	Round  int    `json:"round"` // 轮次，从 1 起
+	Board  int    `json:"board"` // 台次，从 1 起
+	Black  int    `json:"black"` // 执黑引擎的下标
+	White  int    `json:"white"` // 执白引擎的下标；Bye 表示轮空
+	Done   bool   `json:"done"`
+	Result string `json:"result,omitempty"` // record.ResultToken 的记法："1-0"、"0-1" 或 "1/2-1/2"
+	Record string `json:"record,omitempty"` // 对局记录文件，相对赛事目录
+}
+
+// blackPoints 返回执黑一方的得分：胜 1、和 0.5、负 0；轮空计 1 分
+func (p Pairing) blackPoints() float64 {
+	switch {
+	case p.White == Bye, p.Result == "1-0":
+		return 1
+	case p.Result == "0-1":
+		return 0
+	}
+	return 0.5
+}
+
+/* ---------- 循环赛 ---------- */
+
+// roundRobin 用轮转法（Berger 表）排出 n 个引擎 cycles 个循环的全部轮次。
+// 每个循环内每对引擎相遇一次，奇数循环交换先后手，人数为奇数时每轮有一人休息（不计分）。
+func roundRobin(n, cycles int) [][]Pairing {
+	m := n + n%2
+	var rounds [][]Pairing
+	for c := 0; c < cycles; c++ {
+		ring := make([]int, m)
+		for i := range ring {
+			ring[i] = i
+		}
+		for r := 0; r < m-1; r++ {
+			var round []Pairing
+			for i := 0; i < m/2; i++ {
+				a, b := ring[i], ring[m-1-i]
+				if a >= n || b >= n {
+					continue
+				}
+				// 固定位置的一方逐轮交替先后手，其余台次按台号奇偶交替
+				if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 0) {
+					a, b = b, a
+				}
+				if c%2 == 1 {
+					a, b = b, a
+				}
+				round = append(round, Pairing{Round: len(rounds) + 1, Board: len(round) + 1, Black: a, White: b})
+			}
+			rounds = append(rounds, round)
+			// 除第一个位置外整体右移一位
+			last := ring[m-1]
+			copy(ring[2:], ring[1:m-1])
+			ring[1] = last
+		}
+	}
+	return rounds
+}
+
+/* ---------- 瑞士制 ---------- */
+
+// history 汇总已完成轮次中每个引擎的得分、对手与先后手
+type history struct {
+	points   []float64
+	met      []map[int]bool
+	colorBal []int  // 执黑次数减执白次数
+	lastBlk  []bool // 上一局是否执黑
+	hadBye   []bool
+}
+
+func newHistory(n int, rounds [][]Pairing) *history {
+	h := &history{
+		points:   make([]float64, n),
+		met:      make([]map[int]bool, n),
+		colorBal: make([]int, n),
+		lastBlk:  make([]bool, n),
+		hadBye:   make([]bool, n),
+	}
+	for i := range h.met {
+		h.met[i] = map[int]bool{}
+	}
+	for _, round := range rounds {
+		for _, p := range round {
+			if p.White == Bye {
+				h.hadBye[p.Black] = true
+				h.points[p.Black]++
+				continue
+			}
+			h.met[p.Black][p.White] = true
+			h.met[p.White][p.Black] = true
+			h.colorBal[p.Black]++
+			h.colorBal[p.White]--
+			h.lastBlk[p.Black], h.lastBlk[p.White] = true, false
+			if p.Done {
+				pts := p.blackPoints()
+				h.points[p.Black] += pts
+				h.points[p.White] += 1 - pts
+			}
+		}
+	}
+	return h
+}
+
+// swissRound 按当前积分排出第 round 轮：积分高者相邻配对并尽量避免重复相遇，
+// 人数为奇数时积分最低且未轮空过的引擎轮空；先后手偏向执黑较少的一方。
+func swissRound(n, round int, prev [][]Pairing) []Pairing {
+	h := newHistory(n, prev)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return h.points[order[i]] > h.points[order[j]]
+	})
+
+	bye := -1
+	if n%2 == 1 {
+		at := len(order) - 1
+		for i := len(order) - 1; i >= 0; i-- {
+			if !h.hadBye[order[i]] {
+				at = i
+				break
+			}
+		}
+		bye = order[at]
+		order = append(order[:at:at], order[at+1:]...)
+	}
+
+	pairs, ok := pairUp(order, h, false)
+	if !ok {
+		pairs, _ = pairUp(order, h, true)
+	}
+	var out []Pairing
+	for i, pr := range pairs {
+		a, b := pr[0], pr[1]
+		switch {
+		case h.colorBal[a] > h.colorBal[b]:
+			a, b = b, a
+		case h.colorBal[a] == h.colorBal[b] && h.lastBlk[a] != h.lastBlk[b]:
+			if h.lastBlk[a] {
+				a, b = b, a
+			}
+		case h.colorBal[a] == h.colorBal[b] && (round+i)%2 == 0:
+			a, b = b, a
+		}
+		out = append(out, Pairing{Round: round, Board: i + 1, Black: a, White: b})
+	}
+	if bye >= 0 {
+		out = append(out, Pairing{Round: round, Board: len(out) + 1, Black: bye, White: Bye, Done: true})
+	}
+	return out
+}
+
+// pairUp 把 order（按积分排好）两两配对：每次为排名最前者找排名最近的可用对手，
+// 无解时回溯；rematch 为 true 时允许重复相遇。
+func pairUp(order []int, h *history, rematch bool) ([][2]int, bool) {
+	if len(order) == 0 {
+		return nil, true
+	}
+	first := order[0]
+	for j := 1; j < len(order); j++ {
+		opp := order[j]
+		if !rematch && h.met[first][opp] {
+			continue
+		}
+		rest := make([]int, 0, len(order)-2)
+		rest = append(rest, order[1:j]...)
+		rest = append(rest, order[j+1:]...)
+		if pairs, ok := pairUp(rest, h, rematch); ok {
+			return append([][2]int{{first, opp}}, pairs...), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,275 @@
+// File tournament/tournament.go
+package tournament
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/match"
+	"trackLogicChess/internal/record"
+)
+
+// 赛事目录的内容：
+//   - tournament.json：配置与全部轮次的安排和结果，每局结束后原子地重写，据此断点续赛；
+//   - crosstable.txt：当前成绩表；
+//   - games/：每局的对局记录，例如 games/round-01-board-02.txt。
+const (
+	stateFile      = "tournament.json"
+	crosstableFile = "crosstable.txt"
+	gamesDir       = "games"
+)
+
+var (
+	ErrNoState        = errors.New("tournament: no tournament in directory")
+	ErrConfigMismatch = errors.New("tournament: config differs from the one being resumed")
+)
+
+// Factory 把引擎说明解释为每局新建 Player 的函数。
+type Factory func(spec string) (func(rng *rand.Rand) game.Player, error)
+
+// Tournament 为一个保存在目录中的赛事。
+type Tournament struct {
+	dir     string
+	cfg     Config
+	engines []match.Engine
+	rots    [][2]game.Direction
+
+	mu     sync.Mutex
+	rounds [][]Pairing
+}
+
+// state 为 tournament.json 的内容
+type state struct {
+	Config Config      `json:"config"`
+	Rounds [][]Pairing `json:"rounds"`
+}
+
+// Open 在目录 dir 中新建或恢复赛事。
+// 目录中已有赛事时恢复之：cfg 为 nil 表示沿用保存的配置，否则除 Concurrency 外须与保存的配置一致；
+// 没有赛事时 cfg 不能为 nil。
+func Open(dir string, cfg *Config, newPlayer Factory) (*Tournament, error) {
+	t := &Tournament{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	switch {
+	case err == nil:
+		var st state
+		if err := json.Unmarshal(data, &st); err != nil {
+			return nil, fmt.Errorf("tournament: %s: %w", stateFile, err)
+		}
+		t.cfg, t.rounds = st.Config, st.Rounds
+		if cfg != nil {
+			want := *cfg
+			want.Concurrency = t.cfg.Concurrency
+			if want.Seed == 0 {
+				want.Seed = t.cfg.Seed
+			}
+			if !reflect.DeepEqual(want, t.cfg) {
+				return nil, ErrConfigMismatch
+			}
+			t.cfg.Concurrency = cfg.Concurrency
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	case cfg == nil:
+		return nil, ErrNoState
+	default:
+		t.cfg = *cfg
+		if t.cfg.Seed == 0 {
+			t.cfg.Seed = time.Now().UnixNano()
+		}
+	}
+	if err := t.cfg.Validate(); err != nil {
+		return nil, err
+	}
+	t.rots, _ = t.cfg.rotations()
+	for _, def := range t.cfg.Engines {
+		f, err := newPlayer(def.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("tournament: engine %s: %w", def.Name, err)
+		}
+		t.engines = append(t.engines, match.Engine{Name: def.Name, New: f})
+	}
+	if t.rounds == nil {
+		if err := os.MkdirAll(filepath.Join(dir, gamesDir), 0o755); err != nil {
+			return nil, err
+		}
+		if t.cfg.Format == RoundRobin {
+			t.rounds = roundRobin(len(t.engines), t.cfg.cycles())
+		}
+		if err := t.save(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Config 返回赛事配置。
+func (t *Tournament) Config() Config {
+	return t.cfg
+}
+
+// Rounds 返回已排出的轮次（副本）。
+func (t *Tournament) Rounds() [][]Pairing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([][]Pairing, len(t.rounds))
+	for i, r := range t.rounds {
+		out[i] = append([]Pairing(nil), r...)
+	}
+	return out
+}
+
+// Finished 报告赛事是否已全部完成。
+func (t *Tournament) Finished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending()) == 0 && !t.moreRounds()
+}
+
+// Run 下完所有尚未完成的对局，每完成一局调用一次 onGame（可为 nil）。
+// 瑞士制在上一轮全部结束后才排下一轮。ctx 取消时放弃进行中的对局并返回 ctx.Err()，
+// 之后可用 Open 恢复并再次 Run。
+func (t *Tournament) Run(ctx context.Context, onGame func(Pairing)) error {
+	for {
+		t.mu.Lock()
+		batch := t.pending()
+		if len(batch) == 0 {
+			if !t.moreRounds() {
+				t.mu.Unlock()
+				return nil
+			}
+			round := len(t.rounds) + 1
+			t.rounds = append(t.rounds, swissRound(len(t.engines), round, t.rounds))
+			err := t.save()
+			t.mu.Unlock()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		t.mu.Unlock()
+		if err := t.runBatch(ctx, batch, onGame); err != nil {
+			return err
+		}
+	}
+}
+
+// pending 返回尚未完成的对局（调用方持有 mu）
+func (t *Tournament) pending() []Pairing {
+	var out []Pairing
+	for _, round := range t.rounds {
+		for _, p := range round {
+			if !p.Done {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// moreRounds 报告瑞士制是否还有未排的轮次（调用方持有 mu）
+func (t *Tournament) moreRounds() bool {
+	return t.cfg.Format == Swiss && len(t.rounds) < t.cfg.Rounds
+}
+
+// runBatch 并发下完 batch 中的对局
+func (t *Tournament) runBatch(ctx context.Context, batch []Pairing, onGame func(Pairing)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	next := make(chan Pairing)
+	for w := 0; w < max(t.cfg.Concurrency, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range next {
+				if err := t.play(ctx, p, onGame); err != nil {
+					errMu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+feed:
+	for _, p := range batch {
+		select {
+		case next <- p:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// play 下一局并保存记录、状态与成绩表
+func (t *Tournament) play(ctx context.Context, p Pairing, onGame func(Pairing)) error {
+	rot := t.rots[(p.Round+p.Board)%len(t.rots)]
+	seed := t.cfg.Seed + int64(p.Round)<<20 + int64(p.Board)<<4
+	start, err := match.RandomOpening(rot[0], rot[1], t.cfg.OpeningPlies, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return fmt.Errorf("tournament: round %d board %d: %w", p.Round, p.Board, err)
+	}
+	rec, err := match.PlayGame(ctx, start, t.engines[p.Black], t.engines[p.White], seed+1, seed+2)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("tournament: round %d board %d: %w", p.Round, p.Board, err)
+	}
+	p.Record = filepath.Join(gamesDir, fmt.Sprintf("round-%02d-board-%02d.txt", p.Round, p.Board))
+	if err := rec.Save(filepath.Join(t.dir, p.Record)); err != nil {
+		return err
+	}
+	p.Result = record.ResultToken(rec.Result)
+	p.Done = true
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rounds[p.Round-1][p.Board-1] = p
+	if err := t.save(); err != nil {
+		return err
+	}
+	if err := t.writeCrosstableFile(); err != nil {
+		return err
+	}
+	if onGame != nil {
+		onGame(p)
+	}
+	return nil
+}
+
+// save 原子地重写 tournament.json（调用方持有 mu）
+func (t *Tournament) save() error {
+	data, err := json.MarshalIndent(state{Config: t.cfg, Rounds: t.rounds}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(t.dir, stateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,217 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/record"
+)
+
+// testFactory 解释 "random" 与 "depth=N" 两种说明
+func testFactory(spec string) (func(rng *rand.Rand) game.Player, error) {
+	if spec == "random" {
+		return func(rng *rand.Rand) game.Player {
+			return game.PlayerFunc(func(ctx context.Context, g *game.GameState) (game.Move, error) {
+				moves := g.GenerateMoves()
+				return moves[rng.Intn(len(moves))], nil
+			})
+		}, nil
+	}
+	d, ok := strings.CutPrefix(spec, "depth=")
+	if !ok {
+		return nil, fmt.Errorf("bad spec %q", spec)
+	}
+	depth, err := strconv.Atoi(d)
+	if err != nil {
+		return nil, err
+	}
+	return func(rng *rand.Rand) game.Player {
+		return &game.EnginePlayer{
+			Searcher: game.NewSearcherWith(game.Options{TTBits: 14, Rand: rng}),
+			Limits:   game.Limits{Depth: depth},
+		}
+	}, nil
+}
+
+func testConfig(format string, specs ...string) *Config {
+	cfg := &Config{Format: format, OpeningPlies: 1, Seed: 5, Concurrency: 2}
+	if format == Swiss {
+		cfg.Rounds = 3
+	}
+	for i, s := range specs {
+		cfg.Engines = append(cfg.Engines, EngineDef{Name: fmt.Sprintf("e%d", i), Spec: s})
+	}
+	return cfg
+}
+
+func TestRoundRobinSchedule(t *testing.T) {
+	for n := 2; n <= 7; n++ {
+		rounds := roundRobin(n, 2)
+		met := map[[2]int]int{}
+		balance := make([]int, n)
+		for _, round := range rounds {
+			busy := map[int]bool{}
+			for _, p := range round {
+				if busy[p.Black] || busy[p.White] {
+					t.Fatalf("n=%d round %d: engine plays twice", n, p.Round)
+				}
+				busy[p.Black], busy[p.White] = true, true
+				met[[2]int{p.Black, p.White}]++
+				balance[p.Black]++
+				balance[p.White]--
+			}
+		}
+		for i := 0; i < n; i++ {
+			if balance[i] != 0 {
+				t.Errorf("n=%d: engine %d colour balance %d", n, i, balance[i])
+			}
+			for j := 0; j < n; j++ {
+				if i != j && met[[2]int{i, j}] != 1 {
+					t.Errorf("n=%d: %d as black against %d %d times", n, i, j, met[[2]int{i, j}])
+				}
+			}
+		}
+	}
+}
+
+func TestSwissPairing(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{6, 7, 8} {
+		var rounds [][]Pairing
+		byes := map[int]bool{}
+		for r := 1; r <= 4; r++ {
+			round := swissRound(n, r, rounds)
+			busy := map[int]bool{}
+			for i := range round {
+				p := &round[i]
+				if busy[p.Black] || (p.White != Bye && busy[p.White]) {
+					t.Fatalf("n=%d round %d: engine paired twice", n, r)
+				}
+				busy[p.Black] = true
+				if p.White == Bye {
+					if byes[p.Black] {
+						t.Errorf("n=%d: engine %d got a second bye", n, p.Black)
+					}
+					byes[p.Black] = true
+					continue
+				}
+				busy[p.White] = true
+				if newHistory(n, rounds).met[p.Black][p.White] {
+					t.Errorf("n=%d round %d: rematch %d-%d", n, r, p.Black, p.White)
+				}
+				p.Done, p.Result = true, []string{"1-0", "0-1", "1/2-1/2"}[rng.Intn(3)]
+			}
+			if len(busy) != n {
+				t.Fatalf("n=%d round %d: %d engines paired", n, r, len(busy))
+			}
+			rounds = append(rounds, round)
+		}
+		for i, b := range newHistory(n, rounds).colorBal {
+			if b < -2 || b > 2 {
+				t.Errorf("n=%d: engine %d colour balance %d", n, i, b)
+			}
+		}
+	}
+}
+
+func TestRunAndResume(t *testing.T) {
+	dir := t.TempDir()
+	cfg := testConfig(RoundRobin, "depth=3", "random", "depth=1")
+	tour, err := Open(dir, cfg, testFactory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	played := 0
+	err = tour.Run(ctx, func(p Pairing) {
+		played++
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || played == 0 || tour.Finished() {
+		t.Fatalf("interrupted run: err %v, played %d", err, played)
+	}
+
+	if _, err := Open(dir, testConfig(RoundRobin, "depth=3", "random"), testFactory); !errors.Is(err, ErrConfigMismatch) {
+		t.Errorf("changed config: err %v", err)
+	}
+	tour, err = Open(dir, nil, testFactory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tour.Run(context.Background(), func(p Pairing) { played++ }); err != nil {
+		t.Fatal(err)
+	}
+	if !tour.Finished() || played != 6 {
+		t.Fatalf("finished %v after %d games", tour.Finished(), played)
+	}
+
+	total := 0.0
+	for _, s := range tour.Standings() {
+		total += s.Points
+	}
+	if total != 6 {
+		t.Errorf("standings hold %v points, want 6", total)
+	}
+	for _, round := range tour.Rounds() {
+		for _, p := range round {
+			rec, err := record.Load(filepath.Join(dir, p.Record))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := rec.Replay(); err != nil || rec.Black != cfg.Engines[p.Black].Name {
+				t.Errorf("%s: %v (black %q)", p.Record, err, rec.Black)
+			}
+		}
+	}
+	table, err := os.ReadFile(filepath.Join(dir, crosstableFile))
+	if err != nil || !strings.Contains(string(table), "6/6 games played") {
+		t.Errorf("crosstable:\n%s (%v)", table, err)
+	}
+}
+
+func TestRunSwiss(t *testing.T) {
+	dir := t.TempDir()
+	tour, err := Open(dir, testConfig(Swiss, "depth=3", "random", "depth=1", "random", "depth=2"), testFactory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tour.Run(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	rounds := tour.Rounds()
+	if len(rounds) != 3 || !tour.Finished() {
+		t.Fatalf("%d rounds, finished %v", len(rounds), tour.Finished())
+	}
+	total := 0.0
+	for _, s := range tour.Standings() {
+		total += s.Points
+	}
+	if total != 9 { // 每轮两局加一次轮空
+		t.Errorf("standings hold %v points, want 9", total)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []*Config{
+		{Format: "knockout", Engines: []EngineDef{{"a", ""}, {"b", ""}}},
+		{Format: Swiss, Engines: []EngineDef{{"a", ""}, {"b", ""}}},
+		{Format: RoundRobin, Engines: []EngineDef{{"a", ""}}},
+		{Format: RoundRobin, Engines: []EngineDef{{"a", ""}, {"a", ""}}},
+		{Format: RoundRobin, Rotations: []string{"cw"}, Engines: []EngineDef{{"a", ""}, {"b", ""}}},
+		{Format: RoundRobin, OpeningPlies: -1, Engines: []EngineDef{{"a", ""}, {"b", ""}}},
+		{Format: RoundRobin, OpeningPlies: 16, Engines: []EngineDef{{"a", ""}, {"b", ""}}},
+	}
+	for i, cfg := range bad {
+		if err := cfg.Validate(); !errors.Is(err, ErrConfig) {
+			t.Errorf("case %d: err %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// File tune/samples.go
+package tune
+
+import (
+	"context"
+	"math/rand"
+
+	"trackLogicChess/internal/game"
+	"trackLogicChess/internal/match"
+	"trackLogicChess/internal/player"
+	"trackLogicChess/internal/record"
+	"trackLogicChess/internal/solver"
+)
+
+// FromRecords 从对局记录中取样：每局前 skip 步之后的每个未结束局面，以该局的最终结果为目标。
+// 未结束的对局被跳过。
+func FromRecords(recs []*record.Record, skip int) ([]Sample, error) {
+	var out []Sample
+	for _, rec := range recs {
+		if !rec.Result.Over() {
+			continue
+		}
+		states, err := rec.Replay()
+		if err != nil {
+			return nil, err
+		}
+		for i, g := range states {
+			if i < skip || g.IsGameOver() {
+				continue
+			}
+			out = append(out, Sample{Features: game.EvalFeatures(g), Target: points(rec.Result, g.CurrentPlayer)})
+		}
+	}
+	return out, nil
+}
+
+// points 返回结果 r 对 col 一方的得分
+func points(r game.Result, col player.Color) float64 {
+	switch r.Winner() {
+	case col:
+		return 1
+	case player.Empty:
+		return 0.5
+	}
+	return 0
+}
+
+// SelfPlayConfig 为自对弈取样的设置。
+type SelfPlayConfig struct {
+	Games        int          // 对局数
+	Depth        int          // 双方的搜索深度
+	OpeningPlies int          // 随机开局步数
+	Weights      game.Weights // 双方所用的评估权重
+	Concurrency  int
+	Seed         int64
+}
+
+// SelfPlay 让同一引擎自对弈 cfg.Games 局（四种旋转配置轮换），返回对局记录。
+func SelfPlay(ctx context.Context, cfg SelfPlayConfig) ([]*record.Record, error) {
+	w := cfg.Weights
+	engine := match.Engine{Name: "selfplay", New: func(rng *rand.Rand) game.Player {
+		return &game.EnginePlayer{
+			Searcher: game.NewSearcherWith(game.Options{TTBits: 16, Rand: rng, Weights: &w}),
+			Limits:   game.Limits{Depth: cfg.Depth},
+		}
+	}}
+	var recs []*record.Record
+	_, err := match.Run(ctx, match.Config{
+		A:           engine,
+		B:           engine,
+		Games:       cfg.Games,
+		Concurrency: cfg.Concurrency,
+		RandomPlies: cfg.OpeningPlies,
+		Seed:        cfg.Seed,
+	}, func(g match.Game, _ match.Summary) {
+		recs = append(recs, g.Record)
+	})
+	return recs, err
+}
+
+// FromSolver 在 (dirOuter, dirInner) 配置下取 n 个不同的未结束局面，以求解表 t 的精确结果为目标；
+// 每个局面由 match.RandomOpening 随机走 0 … match.MaxRandomPlies 步得到。
+// 配置须为 t 的配置或其镜像配置（见 solver.Table）。
+func FromSolver(t *solver.Table, dirOuter, dirInner game.Direction, n int, rng *rand.Rand) []Sample {
+	var out []Sample
+	seen := map[uint64]bool{}
+	for tries := 0; len(out) < n && tries < 100*n; tries++ {
+		g, err := match.RandomOpening(dirOuter, dirInner, rng.Intn(match.MaxRandomPlies+1), rng)
+		if err != nil || seen[g.Key()] {
+			continue
+		}
+		seen[g.Key()] = true
+		out = append(out, Sample{Features: game.EvalFeatures(g), Target: solverPoints(t.Value(g).Outcome())})
+	}
+	return out
+}
+
+// solverPoints 把求解结果换算为走棋方的得分
+func solverPoints(o solver.Outcome) float64 {
+	switch o {
+	case solver.Win:
+		return 1
+	case solver.Loss:
+		return 0
+	}
+	return 0.5
+}
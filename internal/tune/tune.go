@@ -0,0 +1,133 @@
+// File tune/tune.go
+package tune
+
+import (
+	"math"
+
+	"trackLogicChess/internal/game"
+)
+
+// Texel 式调参：
+//   - 每个样本为一个局面的特征向量与走棋方的实际得分（胜 1、和 0.5、负 0）；
+//   - 以 sigmoid(K·评分) 作为预测得分，误差为全部样本的均方误差；
+//   - 先对起始权重拟合缩放常数 K，再固定 K，对各权重做步长递减的坐标下降。
+
+// Sample 为一个训练局面。
+type Sample struct {
+	Features game.Features
+	Target   float64 // 走棋方的得分：胜 1、和 0.5、负 0
+}
+
+// Options 为调参选项，零值字段使用默认值。
+type Options struct {
+	Steps     []int                                       // 坐标下降的步长序列，默认 16、8、4、2、1
+	MaxPasses int                                         // 每个步长最多遍历全部参数的次数，默认 50
+	Fixed     []int                                       // 不参与调整的特征下标（game.Feat*）
+	Progress  func(step int, err float64, w game.Weights) // 每完成一遍调用一次，可为 nil
+}
+
+// Result 为调参结果。
+type Result struct {
+	Weights game.Weights
+	K       float64 // 拟合出的缩放常数
+	Start   float64 // 起始权重的误差
+	Error   float64 // 调参后的误差
+	Passes  int     // 共遍历参数的次数
+}
+
+// sigmoid 把评分换算为预测得分
+func sigmoid(k float64, score int) float64 {
+	return 1 / (1 + math.Exp(-k*float64(score)))
+}
+
+// evaluate 以权重向量 v 计算样本评分
+func evaluate(v *game.Features, f *game.Features) int {
+	s := 0
+	for i := range f {
+		s += v[i] * f[i]
+	}
+	return s
+}
+
+// Error 返回权重 w、缩放常数 k 下全部样本的均方误差。
+func Error(samples []Sample, w game.Weights, k float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	v := w.Vector()
+	sum := 0.0
+	for i := range samples {
+		d := samples[i].Target - sigmoid(k, evaluate(&v, &samples[i].Features))
+		sum += d * d
+	}
+	return sum / float64(len(samples))
+}
+
+// FitK 求使误差最小的缩放常数 k（在对数尺度上做黄金分割搜索）。
+func FitK(samples []Sample, w game.Weights) float64 {
+	lo, hi := math.Log(1e-5), math.Log(10.0)
+	f := func(x float64) float64 { return Error(samples, w, math.Exp(x)) }
+	const phi = 0.6180339887498949
+	a, b := hi-phi*(hi-lo), lo+phi*(hi-lo)
+	fa, fb := f(a), f(b)
+	for i := 0; i < 60; i++ {
+		if fa < fb {
+			hi, b, fb = b, a, fa
+			a = hi - phi*(hi-lo)
+			fa = f(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + phi*(hi-lo)
+			fb = f(b)
+		}
+	}
+	return math.Exp((lo + hi) / 2)
+}
+
+// Tune 从权重 start 出发调参。
+func Tune(samples []Sample, start game.Weights, opts Options) Result {
+	steps := opts.Steps
+	if len(steps) == 0 {
+		steps = []int{16, 8, 4, 2, 1}
+	}
+	maxPasses := opts.MaxPasses
+	if maxPasses <= 0 {
+		maxPasses = 50
+	}
+	fixed := map[int]bool{}
+	for _, i := range opts.Fixed {
+		fixed[i] = true
+	}
+
+	k := FitK(samples, start)
+	v := start.Vector()
+	best := Error(samples, start, k)
+	res := Result{K: k, Start: best}
+	for _, step := range steps {
+		for pass := 0; pass < maxPasses; pass++ {
+			res.Passes++
+			improved := false
+			for i := range v {
+				if fixed[i] {
+					continue
+				}
+				for _, d := range []int{step, -step} {
+					v[i] += d
+					if e := Error(samples, game.WeightsFromVector(v), k); e < best {
+						best, improved = e, true
+						break
+					}
+					v[i] -= d
+				}
+			}
+			if opts.Progress != nil {
+				opts.Progress(step, best, game.WeightsFromVector(v))
+			}
+			if !improved {
+				break
+			}
+		}
+	}
+	res.Weights, res.Error = game.WeightsFromVector(v), best
+	return res
+}
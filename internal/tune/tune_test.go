@@ -0,0 +1,76 @@
+package tune
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"trackLogicChess/internal/game"
+)
+
+// syntheticSamples 以权重 w、缩放常数 k 生成理想的期望得分作为目标
+func syntheticSamples(rng *rand.Rand, w game.Weights, k float64, n int) []Sample {
+	var out []Sample
+	for len(out) < n {
+		g := game.NewGame(game.Direction(rng.Intn(2)), game.Direction(rng.Intn(2)))
+		for !g.IsGameOver() && len(out) < n {
+			f := game.EvalFeatures(g)
+			v := w.Vector()
+			out = append(out, Sample{Features: f, Target: sigmoid(k, evaluate(&v, &f))})
+			moves := g.GenerateMoves()
+			mv := moves[rng.Intn(len(moves))]
+			_ = g.ApplyMove(mv.Row, mv.Col)
+		}
+	}
+	return out
+}
+
+func TestFitK(t *testing.T) {
+	w := game.DefaultWeights()
+	samples := syntheticSamples(rand.New(rand.NewSource(1)), w, 0.02, 2000)
+	if k := FitK(samples, w); math.Abs(k-0.02) > 0.001 {
+		t.Errorf("k = %v, want 0.02", k)
+	}
+}
+
+func TestTuneRecoversWeights(t *testing.T) {
+	truth := game.Weights{Lines: [3]int{2, 10, 40}, Inner: 3, Rotated: [3]int{1, 6, 30}}
+	samples := syntheticSamples(rand.New(rand.NewSource(2)), truth, 0.03, 3000)
+	start := game.DefaultWeights()
+	res := Tune(samples, start, Options{Fixed: []int{game.FeatLine3}})
+	if res.Error >= res.Start {
+		t.Fatalf("error did not improve: %v -> %v", res.Start, res.Error)
+	}
+	if res.Weights.Lines[2] != start.Lines[2] {
+		t.Errorf("fixed weight changed: %v", res.Weights.Lines[2])
+	}
+	if res.Weights.Rotated[2] <= 0 || res.Weights.Inner <= 0 {
+		t.Errorf("tuned weights %+v missed the rotation and inner-ring terms", res.Weights)
+	}
+}
+
+func TestSelfPlaySamples(t *testing.T) {
+	recs, err := SelfPlay(context.Background(), SelfPlayConfig{
+		Games: 4, Depth: 2, OpeningPlies: 2, Weights: game.DefaultWeights(), Seed: 3,
+	})
+	if err != nil || len(recs) != 4 {
+		t.Fatalf("%d records, %v", len(recs), err)
+	}
+	samples, err := FromRecords(recs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 0
+	for _, rec := range recs {
+		want += len(rec.Plies) - 2 // 去掉开局两步之前的局面与终局
+	}
+	if len(samples) != want {
+		t.Fatalf("%d samples, want %d", len(samples), want)
+	}
+	for _, s := range samples {
+		if s.Target != 0 && s.Target != 0.5 && s.Target != 1 {
+			t.Fatalf("target %v", s.Target)
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package gui
 
 import (
+	"context"
+	"log"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"trackLogicChess/internal/game"
-	"trackLogicChess/internal/player"
 )
 
 const (
@@ -14,27 +15,59 @@ const (
 	aiDelay = 200 * time.Millisecond
 )
 
-// App 实现 ebiten.Game，管理输入、AI、动画与渲染
+// moveEvent 是驱动器走完一步后送给界面的快照
+type moveEvent struct {
+	prev  *game.Board     // 落子前的棋盘（动画起点）
+	next  *game.GameState // 落子并旋转后的局面
+	human bool            // 是否由 GUI 人类玩家走出
+}
+
+// App 实现 ebiten.Game，管理输入、动画与渲染。
+// 对局由 game.Driver 在后台 goroutine 中推进，每走一步经 events 送回界面，
+// 界面只读写自己的局面副本 view，不会与驱动器争用同一个 GameState。
 type App struct {
-	state      *game.GameState
-	useAI      bool
+	view       *game.GameState
+	driver     *game.Driver
+	events     chan moveEvent
+	started    bool
 	anim       animator
 	imgA, imgB *ebiten.Image
 
-	// AI 延迟缓存
-	pendingPrev *game.Board
-	pendingRC   [2]int
-	pendingTime time.Time
-	lastAI      time.Time
+	pending   *moveEvent // 已收到、尚未显示的一步
+	clicked   bool       // 已把点击交给 Human，等待驱动器走子
+	idleSince time.Time  // 上一步显示完毕的时间，用于 AI 落子延迟
+	hints     bool       // 人类回合是否显示战术提示（H 键切换）
 }
 
-// Update 处理输入、AI 触发和动画逻辑
+// run 在后台运行驱动器，直到对局结束
+func (a *App) run() {
+	prev := a.driver.Session.State.Board.Clone()
+	a.driver.OnMove = func(mv game.Move, g *game.GameState) {
+		_, human := a.driver.PlayerFor(g.CurrentPlayer).(*Human)
+		if !g.IsGameOver() {
+			// 走完后已换手，走棋的是另一方
+			_, human = a.driver.PlayerFor(g.CurrentPlayer.Opponent()).(*Human)
+		}
+		a.events <- moveEvent{prev: prev, next: g.Clone(), human: human}
+		prev = g.Board.Clone()
+	}
+	if _, err := a.driver.Run(context.Background()); err != nil {
+		log.Println("对局中止：", err)
+	}
+}
+
+// Update 处理输入、驱动器事件和动画逻辑
 func (a *App) Update() error {
-	if a.state.IsGameOver() {
-		return nil
+	if !a.started {
+		a.started = true
+		a.idleSince = time.Now()
+		go a.run()
 	}
 
 	now := time.Now()
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		a.hints = !a.hints
+	}
 
 	// —— 1) 先更新动画（不提前 return），然后检测是否“刚结束” —— //
 	wasActive := a.anim.active
@@ -44,57 +77,50 @@ func (a *App) Update() error {
 	// 刚从 active -> 非 active：立刻退省电
 	if wasActive && !a.anim.active {
 		leavePerf()
+		a.idleSince = now
 	}
 
-	// 动画仍在进行：这帧不处理落子与AI
+	// 动画仍在进行：这帧不处理落子
 	if a.anim.active {
 		return nil
 	}
 
-	// —— 2) AI 回合（带延迟） —— //
-	if a.useAI && a.state.CurrentPlayer == player.White {
-		// 第一次触发：计算一步并记录时间
-		if a.pendingPrev == nil {
-			a.pendingPrev = a.state.Board.Clone()
-			mv := game.FindBestMoveDeep(a.state, 6)
-			a.pendingRC = [2]int{mv.Row, mv.Col}
-			a.pendingTime = now
+	// —— 2) 显示驱动器走出的下一步（AI 着法带延迟） —— //
+	if a.pending == nil {
+		select {
+		case ev := <-a.events:
+			a.pending = &ev
+		default:
 		}
-		// 到点执行落子 + 启动动画（先进高性能）
-		if now.Sub(a.pendingTime) >= aiDelay {
-			_ = a.state.ApplyMove(a.pendingRC[0], a.pendingRC[1])
-			enterPerf()
-			a.anim.Start(
-				a.pendingPrev,
-				a.state.Board,
-				a.state.DirOuter,
-				a.state.DirInner,
-				a.imgA,
-				a.imgB,
-			)
-			a.pendingPrev = nil
+	}
+	if a.pending != nil {
+		if !a.pending.human && now.Sub(a.idleSince) < aiDelay {
+			return nil
 		}
+		a.view = a.pending.next
+		a.clicked = false
+		enterPerf()
+		a.anim.Start(
+			a.pending.prev,
+			a.view.Board,
+			a.view.DirOuter,
+			a.view.DirInner,
+			a.imgA,
+			a.imgB,
+		)
+		a.pending = nil
 		return nil
 	}
 
-	// —— 3) 人类回合：点击立刻落子并动画 —— //
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	// —— 3) 人类回合：点击交给 Human，由驱动器落子 —— //
+	human, ok := a.driver.PlayerFor(a.view.CurrentPlayer).(*Human)
+	if ok && !a.clicked && !a.view.IsGameOver() && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		x, y := ebiten.CursorPosition()
 		r := (y - boardOriginY) / cellSize
 		c := (x - boardOriginX) / cellSize
-		if r >= 0 && r < 4 && c >= 0 && c < 4 && a.state.Board.IsEmpty(r, c) {
-			prev := a.state.Board.Clone()
-			if err := a.state.ApplyMove(r, c); err == nil {
-				enterPerf()
-				a.anim.Start(
-					prev,
-					a.state.Board,
-					a.state.DirOuter,
-					a.state.DirInner,
-					a.imgA,
-					a.imgB,
-				)
-			}
+		if r >= 0 && r < 4 && c >= 0 && c < 4 && a.view.Board.IsEmpty(r, c) {
+			human.moves <- game.Move{Row: r, Col: c}
+			a.clicked = true
 		}
 	}
 	if !booted {
@@ -106,35 +132,29 @@ func (a *App) Update() error {
 	return nil
 }
 
-// Draw 渲染：动画中、AI延迟预览、默认渲染
+// Draw 渲染：动画中或静态棋盘
 func (a *App) Draw(screen *ebiten.Image) {
 	// 1) 动画进行中
 	if a.anim.active {
 		a.anim.Draw(screen,
-			a.state.Board,
-			a.state.DirOuter,
-			a.state.DirInner,
+			a.view.Board,
+			a.view.DirOuter,
+			a.view.DirInner,
 			a.imgA,
 			a.imgB,
 		)
 		return
 	}
-	// 2) AI 延迟预览阶段，仅画原始棋盘
-	if a.pendingPrev != nil {
-		DrawBoard(screen,
-			a.pendingPrev,
-			a.imgA, a.imgB,
-			a.state.DirOuter, a.state.DirInner,
-		)
-		return
-	}
-	// 3) 默认完整渲染
+	// 2) 默认完整渲染
 	DrawBoard(screen,
-		a.state.Board,
+		a.view.Board,
 		a.imgA, a.imgB,
-		a.state.DirOuter, a.state.DirInner,
+		a.view.DirOuter, a.view.DirInner,
 	)
-	if !a.anim.active && a.pendingPrev == nil {
+	if _, human := a.driver.PlayerFor(a.view.CurrentPlayer).(*Human); a.hints && human && !a.view.IsGameOver() {
+		drawHints(screen, a.view.Tactics())
+	}
+	if a.pending == nil {
 		leavePerf()
 	}
 }
@@ -14,11 +14,14 @@ type GUI struct {
 	anim animator
 }
 
-func NewApp(gs *game.GameState, ai bool) *App {
+// NewApp 创建窗口应用，由 black 与 white 从局面 gs 开始对弈。
+// 需要鼠标落子的一方传入 NewHuman() 创建的 Human；其余 Player 在后台 goroutine 中调用。
+func NewApp(gs *game.GameState, black, white game.Player) *App {
 	return &App{
-		state: gs,
-		useAI: ai,
-		imgA:  marbleA,
-		imgB:  marbleB,
+		view:   gs.Clone(),
+		driver: game.NewDriver(gs, black, white),
+		events: make(chan moveEvent),
+		imgA:   marbleA,
+		imgB:   marbleB,
 	}
 }
@@ -0,0 +1,33 @@
+package gui
+
+import (
+	"context"
+
+	"trackLogicChess/internal/game"
+)
+
+// Human 是通过鼠标点击落子的 game.Player。
+// 把它交给 NewApp 作为某一方，轮到该方时窗口中的点击会被转为着法。
+type Human struct {
+	moves chan game.Move
+}
+
+// NewHuman 创建一个 GUI 人类玩家。
+func NewHuman() *Human {
+	return &Human{moves: make(chan game.Move, 1)}
+}
+
+// ChooseMove 实现 game.Player：等待界面送来的点击。
+func (h *Human) ChooseMove(ctx context.Context, g *game.GameState) (game.Move, error) {
+	select {
+	case mv := <-h.moves:
+		return mv, nil
+	case <-ctx.Done():
+		return game.Move{}, ctx.Err()
+	}
+}
+
+// IsHuman 实现 game.HumanPlayer。
+func (h *Human) IsHuman() bool {
+	return true
+}
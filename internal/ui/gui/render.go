@@ -21,6 +21,10 @@ const (
 var (
 	lineColor = color.RGBA{0xff, 0xff, 0xff, 0xff} // 网格白
 	arrowBlue = color.RGBA{0x00, 0x96, 0xff, 0xff} // 箭头蓝
+
+	hintWin   = color.NRGBA{0x2e, 0xcc, 0x40, 0x60} // 提示：一步取胜
+	hintBlock = color.NRGBA{0xff, 0xdc, 0x00, 0x60} // 提示：必须堵截
+	hintLose  = color.NRGBA{0xff, 0x41, 0x36, 0x60} // 提示：落败或送胜
 )
 
 // ────────────────────────────────────────────────────────────
@@ -142,6 +146,23 @@ func drawPieces(screen *ebiten.Image, b *game.Board, imgA, imgB *ebiten.Image) {
 	}
 }
 
+// ──────────────────────────────
+// 4. 战术提示
+// ──────────────────────────────
+// drawHints 以半透明色块标出 t 中的格子：取胜为绿，堵截为黄，落败或送胜为红。
+func drawHints(screen *ebiten.Image, t game.Tactics) {
+	fill := func(s game.CellSet, clr color.Color) {
+		for _, mv := range s.Moves() {
+			x := float32(boardOriginX + mv.Col*cellSize + 1)
+			y := float32(boardOriginY + mv.Row*cellSize + 1)
+			vector.DrawFilledRect(screen, x, y, cellSize-2, cellSize-2, clr, false)
+		}
+	}
+	fill(t.Wins, hintWin)
+	fill(t.Blocks, hintBlock)
+	fill(t.Losses|t.Hangs, hintLose)
+}
+
 // Draw 绘制补间动画，结束后交给 DrawBoard 处理
 func (a *animator) Draw(
 	screen *ebiten.Image,